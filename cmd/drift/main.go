@@ -0,0 +1,115 @@
+// 统计事件日志与 event 包结构体之间的差异
+//
+// 用法: drift [事件日志文件...]
+//
+// 事件日志每行为一条 go-cqhttp 上报的原始JSON, 不指定文件时从标准输入读取
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"koi/pkg/gocqhttp/event"
+	"koi/pkg/gocqhttp/schema"
+)
+
+// 差异统计
+type summary struct {
+	drift schema.Drift
+	count int
+}
+
+func main() {
+	var readers []io.Reader
+
+	for _, path := range os.Args[1:] {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer file.Close()
+
+		readers = append(readers, file)
+	}
+
+	if len(readers) == 0 {
+		readers = append(readers, os.Stdin)
+	}
+
+	events := make(map[string]int)
+	unknown := make(map[string]int)
+	drifts := make(map[string]*summary)
+
+	scanner := bufio.NewScanner(io.MultiReader(readers...))
+	scanner.Buffer(make([]byte, 1<<20), 16<<20)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+
+		v, name, err := event.New(line)
+		if err != nil {
+			continue
+		}
+
+		events[name]++
+
+		if v == nil {
+			unknown[name]++
+			continue
+		}
+
+		for _, drift := range schema.Check(line, v) {
+			key := drift.Type + " " + drift.Path + " " + drift.Kind
+
+			if drifts[key] == nil {
+				drifts[key] = &summary{drift: drift}
+			}
+
+			drifts[key].count++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Println("事件:")
+	for _, name := range sortedKeys(events) {
+		fmt.Printf("  %-40s %d\n", name, events[name])
+	}
+
+	if len(unknown) > 0 {
+		fmt.Println("未定义的事件:")
+		for _, name := range sortedKeys(unknown) {
+			fmt.Printf("  %-40s %d\n", name, unknown[name])
+		}
+	}
+
+	if len(drifts) > 0 {
+		fmt.Println("字段差异:")
+		for _, key := range sortedKeys(drifts) {
+			s := drifts[key]
+			fmt.Printf("  %-24s %-32s %-8s %6d  %s\n", s.drift.Type, s.drift.Path, s.drift.Kind, s.count, s.drift.Detail)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
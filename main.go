@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"koi/pkg/gocqhttp"
+	"koi/pkg/gocqhttp/cqcode"
 	"koi/pkg/gocqhttp/event"
+	"koi/pkg/log"
 
 	"github.com/gorilla/websocket"
 )
@@ -18,6 +20,9 @@ func main() {
 		panic(err)
 	}
 
+	// 预先探测连接能力, 用于降级不支持的消息段
+	gocqhttp.ProbeCapability(ws_api)
+
 	ws_event, _, err := websocket.Dial("ws://localhost:3020/event", nil)
 	if err != nil {
 		panic(err)
@@ -30,36 +35,45 @@ func main() {
 
 // 读取事件
 func Listen(ws_api, ws_event *websocket.Conn) {
-	var event map[string]any
-
 	_, data, err := ws_event.ReadMessage()
 	if err != nil {
 		panic(err)
 	}
 
+	// 解码为对应的事件结构体, 严格模式下会报告与结构体不一致的字段
+	//
+	// * 解码失败时只记录警告, 由处理函数按未知事件处理
+	decoded, name, err := event.Decode(data)
+	if err != nil {
+		log.Warn(fmt.Sprintf("decode event %s: %s", name, err))
+		decoded = nil
+	}
+
+	var event map[string]any
+
 	err = json.Unmarshal(data, &event)
 	if err != nil {
-		panic(err)
+		go HandlerUnknown(ws_api, data)
+		return
 	}
 
 	// 启用协程, 避免阻塞
-	go Switch(ws_api, data, event)
+	go Switch(ws_api, data, event, decoded)
 }
 
 // 事件分支
-func Switch(ws_api *websocket.Conn, data []byte, event map[string]any) {
-	types := event["post_type"].(string)
+func Switch(ws_api *websocket.Conn, data []byte, event map[string]any, decoded any) {
+	types, _ := event["post_type"].(string)
 
 	// 通过"post_type"判断事件类型
 	switch types {
 	case "meta_event":
-		types = event["meta_event_type"].(string)
+		types, _ = event["meta_event_type"].(string)
 		meta(ws_api, types, data)
 	case "message":
-		types = event["message_type"].(string)
-		message(ws_api, types, data)
+		message(ws_api, data, decoded)
 	case "request":
-		types = event["request_type"].(string)
+		types, _ = event["request_type"].(string)
 		request(ws_api, types, data)
 	case "notice":
 		notice(ws_api, data, event)
@@ -81,12 +95,15 @@ func meta(ws_api *websocket.Conn, types string, data []byte) {
 }
 
 // 消息事件
-func message(ws_api *websocket.Conn, types string, data []byte) {
-	switch types {
-	case "private":
-		HandlerPrivateMessage(ws_api, data)
-	case "group":
-		HandlerGroupMessage(ws_api, data)
+func message(ws_api *websocket.Conn, data []byte, decoded any) {
+	// 通过解码后的结构体判断消息类型, 解码失败时按未知事件处理
+	switch message := decoded.(type) {
+	case *event.PrivateMessage:
+		HandlerPrivateMessage(ws_api, message)
+	case *event.GroupMessage:
+		HandlerGroupMessage(ws_api, message)
+	case *event.GuildMessage:
+		HandlerGuildMessage(ws_api, message)
 	default:
 		HandlerUnknown(ws_api, data)
 	}
@@ -106,7 +123,7 @@ func request(ws_api *websocket.Conn, types string, data []byte) {
 
 // 通知事件
 func notice(ws_api *websocket.Conn, data []byte, event map[string]any) {
-	types := event["post_type"].(string)
+	types, _ := event["notice_type"].(string)
 
 	switch types {
 	case "group_admin":
@@ -128,7 +145,7 @@ func notice(ws_api *websocket.Conn, data []byte, event map[string]any) {
 	case "friend_recall":
 		HandlerFriendRecall(ws_api, data)
 	case "notify":
-		types = event["sub_type"].(string)
+		types, _ = event["sub_type"].(string)
 		notify(ws_api, types, data)
 	case "essence":
 		HandlerEssence(ws_api, data)
@@ -136,6 +153,16 @@ func notice(ws_api *websocket.Conn, data []byte, event map[string]any) {
 		HandlerOfflineFile(ws_api, data)
 	case "client_status":
 		HandlerClientStatus(ws_api, data)
+	case "guild_channel_recall":
+		HandlerGuildChannelRecall(ws_api, data)
+	case "message_reactions_updated":
+		HandlerMessageReactionsUpdated(ws_api, data)
+	case "channel_updated":
+		HandlerChannelUpdated(ws_api, data)
+	case "channel_created":
+		HandlerChannelCreated(ws_api, data)
+	case "channel_destroyed":
+		HandlerChannelDestroyed(ws_api, data)
 	default:
 		HandlerUnknown(ws_api, data)
 	}
@@ -150,6 +177,10 @@ func notify(ws_api *websocket.Conn, types string, data []byte) {
 		HandlerLuckyKing(ws_api, data)
 	case "honor":
 		HandlerHonor(ws_api, data)
+	case "title":
+		HandlerTitle(ws_api, data)
+	default:
+		HandlerUnknown(ws_api, data)
 	}
 }
 
@@ -171,16 +202,12 @@ func HandlerHeartbeat(ws_api *websocket.Conn, data []byte) {}
 // * 消息事件
 //
 // 私聊消息
-func HandlerPrivateMessage(ws_api *websocket.Conn, data []byte) {
+func HandlerPrivateMessage(ws_api *websocket.Conn, message *event.PrivateMessage) {
 	// 私聊消息复读示例
-	var message *event.PrivateMessage
+	// 只保留表情, 避免复读被注入的CQ码
+	text := cqcode.Sanitize(message.RawMessage, "face")
 
-	err := json.Unmarshal(data, &message)
-	if err != nil {
-		panic(err)
-	}
-
-	_, err = gocqhttp.SendPrivateMessage(ws_api, message.UserID, message.RawMessage)
+	_, err := gocqhttp.SendPrivateMessage(ws_api, message.UserID, text)
 	if err != nil {
 		panic(err)
 	}
@@ -189,7 +216,12 @@ func HandlerPrivateMessage(ws_api *websocket.Conn, data []byte) {
 // * 消息事件
 //
 // 群消息
-func HandlerGroupMessage(ws_api *websocket.Conn, data []byte) {}
+func HandlerGroupMessage(ws_api *websocket.Conn, message *event.GroupMessage) {}
+
+// * 消息事件
+//
+// 频道消息
+func HandlerGuildMessage(ws_api *websocket.Conn, message *event.GuildMessage) {}
 
 // * 请求事件
 //
@@ -261,6 +293,11 @@ func HandlerLuckyKing(ws_api *websocket.Conn, data []byte) {}
 // 群成员荣誉变更
 func HandlerHonor(ws_api *websocket.Conn, data []byte) {}
 
+// * 通知事件
+//
+// 群成员头衔变更
+func HandlerTitle(ws_api *websocket.Conn, data []byte) {}
+
 // * 通知事件
 //
 // 精华消息
@@ -275,3 +312,28 @@ func HandlerOfflineFile(ws_api *websocket.Conn, data []byte) {}
 //
 // 其他客户端在线状态变更
 func HandlerClientStatus(ws_api *websocket.Conn, data []byte) {}
+
+// * 通知事件
+//
+// 子频道消息撤回
+func HandlerGuildChannelRecall(ws_api *websocket.Conn, data []byte) {}
+
+// * 通知事件
+//
+// 频道消息表情贴更新
+func HandlerMessageReactionsUpdated(ws_api *websocket.Conn, data []byte) {}
+
+// * 通知事件
+//
+// 子频道信息更新
+func HandlerChannelUpdated(ws_api *websocket.Conn, data []byte) {}
+
+// * 通知事件
+//
+// 子频道创建
+func HandlerChannelCreated(ws_api *websocket.Conn, data []byte) {}
+
+// * 通知事件
+//
+// 子频道删除
+func HandlerChannelDestroyed(ws_api *websocket.Conn, data []byte) {}
@@ -0,0 +1,125 @@
+package gocqhttp
+
+// API终结点支持情况
+type Action struct {
+	Name      string   // 终结点名称
+	Functions []string // 发送该终结点的函数, 不含带 Context 后缀的版本, 为空表示尚未支持
+	Upstream  bool     // go-cqhttp 是否支持
+}
+
+// go-cqhttp 的全部API终结点及 koi 的支持情况
+//
+// * 包含OneBot标准中 go-cqhttp 尚未支持的终结点
+var Actions = []Action{
+	// 消息
+	//
+	// * koi 的私聊, 群和临时会话消息都通过 send_msg 发送, 合并转发都通过 send_forward_msg 发送
+	{"send_private_msg", nil, true},
+	{"send_group_msg", nil, true},
+	{"send_msg", []string{"SendPrivateMessage", "SendGroupMessage", "SendTemporaryMessage", "SendPrivateText", "SendGroupText"}, true},
+	{"delete_msg", []string{"DeleteMessage"}, true},
+	{"get_msg", []string{"GetMessage"}, true},
+	{"get_forward_msg", []string{"GetForwardMessage"}, true},
+	{"send_private_forward_msg", nil, true},
+	{"send_group_forward_msg", nil, true},
+	{"send_forward_msg", []string{"SendPrivateForwardMessage", "SendGroupForwardMessage", "SendPrivateForwardMessageID", "SendPrivateForwardMessageCustom", "SendGroupForwardMessageID", "SendGroupForwardMessageCustom"}, true},
+	{"get_image", []string{"GetImage"}, true},
+	{"get_record", []string{"GetRecord"}, false},
+	{"mark_msg_as_read", []string{"MarkMessageRead"}, true},
+	{"get_group_msg_history", []string{"GetGroupMessageHistory"}, true},
+	{"can_send_image", []string{"CanSendImage"}, true},
+	{"can_send_record", []string{"CanSendRecord"}, true},
+
+	// 群管理
+	{"set_group_kick", []string{"SetGroupKick"}, true},
+	{"set_group_ban", []string{"SetGroupBan"}, true},
+	{"set_group_anonymous_ban", []string{"SetGroupAnonymousBan"}, true},
+	{"set_group_whole_ban", []string{"SetGroupWholeBan"}, true},
+	{"set_group_admin", []string{"SetGroupAdmin"}, true},
+	{"set_group_anonymous", []string{"SetGroupAnonymous"}, false},
+	{"set_group_card", []string{"SetGroupCard"}, true},
+	{"set_group_name", []string{"SetGroupName"}, true},
+	{"set_group_leave", []string{"SetGroupLeave"}, true},
+	{"set_group_special_title", []string{"SetGroupSpecialTitle"}, true},
+	{"set_group_portrait", []string{"SetGroupPortrait"}, true},
+	{"send_group_sign", []string{"SendGroupSign"}, true},
+	{"set_essence_msg", []string{"SetEssenceMessage"}, true},
+	{"delete_essence_msg", []string{"DeleteEssenceMessage"}, true},
+	{"get_essence_msg_list", []string{"GetEssenceMessageList"}, true},
+	{"_send_group_notice", []string{"SendGroupNotice"}, true},
+	{"_get_group_notice", []string{"GetGroupNotice"}, true},
+	{"_del_group_notice", []string{"DeleteGroupNotice"}, true},
+	{"get_group_at_all_remain", []string{"GetGroupAtAllRemain"}, true},
+
+	// 请求
+	{"set_friend_add_request", []string{"SetFriendAddRequest"}, true},
+	{"set_group_add_request", []string{"SetGroupAddRequest"}, true},
+	{"get_group_system_msg", []string{"GetGroupSystemMessage"}, true},
+
+	// 账号
+	{"get_login_info", []string{"GetLoginInfo"}, true},
+	{"qidian_get_account_info", []string{"GetQidianAccountInfo"}, true},
+	{"set_qq_profile", []string{"SetProfile"}, true},
+	{"_get_model_show", []string{"GetModelShow"}, true},
+	{"_set_model_show", []string{"SetModelShow"}, true},
+	{"get_online_clients", []string{"GetOnlineClients"}, true},
+
+	// 好友与群信息
+	{"get_stranger_info", []string{"GetStrangerInfo"}, true},
+	{"get_friend_list", []string{"GetFriendList"}, true},
+	{"get_friends_with_category", []string{"GetFriendsWithCategory"}, false},
+	{"get_unidirectional_friend_list", []string{"GetUnidirectionalFriendList"}, true},
+	{"delete_friend", []string{"DeleteFriend"}, true},
+	{"delete_unidirectional_friend", []string{"DeleteUnidirectionalFriend"}, true},
+	{"get_group_info", []string{"GetGroupInfo"}, true},
+	{"get_group_list", []string{"GetGroupList"}, true},
+	{"get_group_member_info", []string{"GetGroupMemberInfo"}, true},
+	{"get_group_member_list", []string{"GetGroupMemberList"}, true},
+	{"get_group_honor_info", []string{"GetGroupHonorInfo"}, true},
+
+	// 群文件
+	{"upload_private_file", []string{"UploadPrivateFile"}, true},
+	{"upload_group_file", []string{"UploadGroupFile"}, true},
+	{"get_group_file_system_info", []string{"GetGroupFileSystemInfo"}, true},
+	{"get_group_root_files", []string{"GetGroupRootFiles"}, true},
+	{"get_group_files_by_folder", []string{"GetGroupFilesByFolder"}, true},
+	{"create_group_file_folder", []string{"CreateGroupFileFolder"}, true},
+	{"delete_group_folder", []string{"DeleteGroupFolder"}, true},
+	{"delete_group_file", []string{"DeleteGroupFile"}, true},
+	{"get_group_file_url", []string{"GetGroupFileURL"}, true},
+
+	// 凭证
+	{"get_cookies", []string{"GetCookies"}, false},
+	{"get_csrf_token", []string{"GetCSRFToken"}, false},
+	{"get_credentials", []string{"GetCredentials"}, false},
+
+	// 工具
+	{".get_word_slices", []string{"GetWordSlices"}, true},
+	{"ocr_image", []string{"OcrImage"}, true},
+	{"check_url_safely", []string{"CheckURLSafely"}, true},
+	{"download_file", []string{"DownloadFile"}, true},
+	{".handle_quick_operation", nil, true},
+
+	// go-cqhttp
+	{"get_version_info", []string{"GetVersionInfo"}, true},
+	{"get_status", []string{"GetStatus"}, true},
+	{"reload_event_filter", []string{"ReloadEventFilter"}, true},
+	{"set_restart", []string{"SetRestart"}, false},
+	{"clean_cache", []string{"CleanCache"}, false},
+
+	// 频道
+	{"get_guild_service_profile", []string{"GetGuildServiceProfile"}, true},
+	{"get_guild_list", []string{"GetGuildList"}, true},
+	{"get_guild_meta_by_guest", []string{"GetGuildMetaByGuest"}, true},
+	{"get_guild_channel_list", []string{"GetGuildChannelList"}, true},
+	{"get_guild_member_list", []string{"GetGuildMemberList"}, true},
+	{"get_guild_member_profile", []string{"GetGuildMemberProfile"}, true},
+	{"send_guild_channel_msg", []string{"SendGuildChannelMessage"}, true},
+	{"get_guild_msg", nil, true},
+	{"get_guild_roles", []string{"GetGuildRoles"}, true},
+	{"create_guild_role", []string{"CreateGuildRole"}, true},
+	{"update_guild_role", []string{"UpdateGuildRole"}, true},
+	{"delete_guild_role", []string{"DeleteGuildRole"}, true},
+	{"set_guild_member_role", []string{"SetGuildMemberRole"}, true},
+	{"get_topic_channel_feeds", nil, true},
+}
@@ -0,0 +1,249 @@
+package gocqhttp
+
+import (
+	"context"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// 解析包内的源码, 返回导出的函数和所有 ws_data 中的终结点
+func parsePackage(t *testing.T) (functions map[string]bool, actions map[string]string) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	functions = make(map[string]bool)
+	actions = make(map[string]string)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, entry.Name(), nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncDecl:
+				if node.Recv == nil && node.Name.IsExported() {
+					functions[node.Name.Name] = true
+				}
+			case *ast.CompositeLit:
+				if ident, ok := node.Type.(*ast.Ident); !ok || ident.Name != "ws_data" {
+					return true
+				}
+
+				for _, element := range node.Elts {
+					kv, ok := element.(*ast.KeyValueExpr)
+					if !ok || kv.Key.(*ast.Ident).Name != "Action" {
+						continue
+					}
+
+					if literal, ok := kv.Value.(*ast.BasicLit); ok {
+						action, _ := strconv.Unquote(literal.Value)
+						actions[action] = fset.Position(literal.Pos()).String()
+					}
+				}
+			}
+
+			return true
+		})
+	}
+
+	return functions, actions
+}
+
+// Actions 中列出的函数
+var actionFunctions = map[string]any{
+	"SendPrivateMessage":              SendPrivateMessage,
+	"SendGroupMessage":                SendGroupMessage,
+	"SendTemporaryMessage":            SendTemporaryMessage,
+	"SendPrivateText":                 SendPrivateText,
+	"SendGroupText":                   SendGroupText,
+	"DeleteMessage":                   DeleteMessage,
+	"GetMessage":                      GetMessage,
+	"GetForwardMessage":               GetForwardMessage,
+	"SendPrivateForwardMessage":       SendPrivateForwardMessage,
+	"SendGroupForwardMessage":         SendGroupForwardMessage,
+	"SendPrivateForwardMessageID":     SendPrivateForwardMessageID,
+	"SendPrivateForwardMessageCustom": SendPrivateForwardMessageCustom,
+	"SendGroupForwardMessageID":       SendGroupForwardMessageID,
+	"SendGroupForwardMessageCustom":   SendGroupForwardMessageCustom,
+	"GetImage":                        GetImage,
+	"GetRecord":                       GetRecord,
+	"MarkMessageRead":                 MarkMessageRead,
+	"GetGroupMessageHistory":          GetGroupMessageHistory,
+	"CanSendImage":                    CanSendImage,
+	"CanSendRecord":                   CanSendRecord,
+	"SetGroupKick":                    SetGroupKick,
+	"SetGroupBan":                     SetGroupBan,
+	"SetGroupAnonymousBan":            SetGroupAnonymousBan,
+	"SetGroupWholeBan":                SetGroupWholeBan,
+	"SetGroupAdmin":                   SetGroupAdmin,
+	"SetGroupAnonymous":               SetGroupAnonymous,
+	"SetGroupCard":                    SetGroupCard,
+	"SetGroupName":                    SetGroupName,
+	"SetGroupLeave":                   SetGroupLeave,
+	"SetGroupSpecialTitle":            SetGroupSpecialTitle,
+	"SetGroupPortrait":                SetGroupPortrait,
+	"SendGroupSign":                   SendGroupSign,
+	"SetEssenceMessage":               SetEssenceMessage,
+	"DeleteEssenceMessage":            DeleteEssenceMessage,
+	"GetEssenceMessageList":           GetEssenceMessageList,
+	"SendGroupNotice":                 SendGroupNotice,
+	"GetGroupNotice":                  GetGroupNotice,
+	"DeleteGroupNotice":               DeleteGroupNotice,
+	"GetGroupAtAllRemain":             GetGroupAtAllRemain,
+	"SetFriendAddRequest":             SetFriendAddRequest,
+	"SetGroupAddRequest":              SetGroupAddRequest,
+	"GetGroupSystemMessage":           GetGroupSystemMessage,
+	"GetLoginInfo":                    GetLoginInfo,
+	"GetQidianAccountInfo":            GetQidianAccountInfo,
+	"SetProfile":                      SetProfile,
+	"GetModelShow":                    GetModelShow,
+	"SetModelShow":                    SetModelShow,
+	"GetOnlineClients":                GetOnlineClients,
+	"GetStrangerInfo":                 GetStrangerInfo,
+	"GetFriendList":                   GetFriendList,
+	"GetFriendsWithCategory":          GetFriendsWithCategory,
+	"GetUnidirectionalFriendList":     GetUnidirectionalFriendList,
+	"DeleteFriend":                    DeleteFriend,
+	"DeleteUnidirectionalFriend":      DeleteUnidirectionalFriend,
+	"GetGroupInfo":                    GetGroupInfo,
+	"GetGroupList":                    GetGroupList,
+	"GetGroupMemberInfo":              GetGroupMemberInfo,
+	"GetGroupMemberList":              GetGroupMemberList,
+	"GetGroupHonorInfo":               GetGroupHonorInfo,
+	"UploadPrivateFile":               UploadPrivateFile,
+	"UploadGroupFile":                 UploadGroupFile,
+	"GetGroupFileSystemInfo":          GetGroupFileSystemInfo,
+	"GetGroupRootFiles":               GetGroupRootFiles,
+	"GetGroupFilesByFolder":           GetGroupFilesByFolder,
+	"CreateGroupFileFolder":           CreateGroupFileFolder,
+	"DeleteGroupFolder":               DeleteGroupFolder,
+	"DeleteGroupFile":                 DeleteGroupFile,
+	"GetGroupFileURL":                 GetGroupFileURL,
+	"GetCookies":                      GetCookies,
+	"GetCSRFToken":                    GetCSRFToken,
+	"GetCredentials":                  GetCredentials,
+	"GetWordSlices":                   GetWordSlices,
+	"OcrImage":                        OcrImage,
+	"CheckURLSafely":                  CheckURLSafely,
+	"DownloadFile":                    DownloadFile,
+	"GetVersionInfo":                  GetVersionInfo,
+	"GetStatus":                       GetStatus,
+	"ReloadEventFilter":               ReloadEventFilter,
+	"SetRestart":                      SetRestart,
+	"CleanCache":                      CleanCache,
+	"GetGuildServiceProfile":          GetGuildServiceProfile,
+	"GetGuildList":                    GetGuildList,
+	"GetGuildMetaByGuest":             GetGuildMetaByGuest,
+	"GetGuildChannelList":             GetGuildChannelList,
+	"GetGuildMemberList":              GetGuildMemberList,
+	"GetGuildMemberProfile":           GetGuildMemberProfile,
+	"SendGuildChannelMessage":         SendGuildChannelMessage,
+	"GetGuildRoles":                   GetGuildRoles,
+	"CreateGuildRole":                 CreateGuildRole,
+	"UpdateGuildRole":                 UpdateGuildRole,
+	"DeleteGuildRole":                 DeleteGuildRole,
+	"SetGuildMemberRole":              SetGuildMemberRole,
+}
+
+// 以零值参数调用函数, 返回发送的终结点
+//
+// * 调用被拦截器直接返回, 不会发送到连接
+func sentActions(t *testing.T, ws *websocket.Conn, function any) (sent []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+
+	withInterceptors(t, func(ctx context.Context, action string, params any, next Handler) (json.RawMessage, error) {
+		mu.Lock()
+		sent = append(sent, action)
+		mu.Unlock()
+
+		return json.RawMessage("null"), nil
+	})
+
+	value := reflect.ValueOf(function)
+
+	var args []reflect.Value
+
+	for i := 0; i < value.Type().NumIn(); i++ {
+		in := value.Type().In(i)
+
+		switch {
+		case in == reflect.TypeOf(ws):
+			args = append(args, reflect.ValueOf(ws))
+		case i == value.Type().NumIn()-1 && value.Type().IsVariadic():
+			// 可变参数为空
+		default:
+			args = append(args, reflect.Zero(in))
+		}
+	}
+
+	value.Call(args)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return append([]string(nil), sent...)
+}
+
+func TestActions(t *testing.T) {
+	functions, actions := parsePackage(t)
+
+	_, ws := newFakeServer(t, nil)
+
+	table := make(map[string]bool)
+
+	for _, action := range Actions {
+		if table[action.Name] {
+			t.Errorf("%s: duplicate entry", action.Name)
+		}
+
+		table[action.Name] = true
+
+		for _, name := range action.Functions {
+			if !functions[name] {
+				t.Errorf("%s: function %s does not exist", action.Name, name)
+				continue
+			}
+
+			function, ok := actionFunctions[name]
+			if !ok {
+				t.Errorf("%s: function %s is missing from actionFunctions", action.Name, name)
+				continue
+			}
+
+			// 函数实际发送的终结点必须与表中一致
+			if sent := sentActions(t, ws, function); len(sent) != 1 || sent[0] != action.Name {
+				t.Errorf("%s: function %s sends %v", action.Name, name, sent)
+			}
+		}
+	}
+
+	for action, position := range actions {
+		if !table[action] {
+			t.Errorf("%s: action %s is not listed in Actions", position, action)
+		}
+	}
+}
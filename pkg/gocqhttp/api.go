@@ -1,85 +1,145 @@
 package gocqhttp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
+
+	"koi/pkg/gocqhttp/cqcode"
+	"koi/pkg/gocqhttp/event"
+	"koi/pkg/gocqhttp/qq"
+	"koi/pkg/gocqhttp/schema"
 
 	"github.com/gorilla/websocket"
 )
 
 // API返回的内容
 type ws_body struct {
-	Body     any     `json:"data"`    // 数据主体
-	Code     *string `json:"msg"`     // 错误代码
-	HTTPCode int     `json:"retcode"` // HTTP状态码
-	Status   string  `json:"status"`  // 响应状态
-	Message  *string `json:"wording"` // 错误信息
+	Body     json.RawMessage `json:"data"`    // 数据主体
+	Code     *string         `json:"msg"`     // 错误代码
+	HTTPCode int             `json:"retcode"` // HTTP状态码
+	Status   string          `json:"status"`  // 响应状态
+	Message  *string         `json:"wording"` // 错误信息
+	Echo     json.RawMessage `json:"echo"`    // 请求标识
 }
 
 // 符合gocqhttp规范的数据
 type ws_data struct {
 	Action string `json:"action"`           // API终结点
 	Params any    `json:"params,omitempty"` // 参数
+	Echo   string `json:"echo,omitempty"`   // 请求标识, 用于匹配响应
 }
 
 var values = make(map[string]any)
 
 func (message ws_data) do(ws *websocket.Conn) ([]byte, error) {
-	var data *ws_body
+	return message.doContext(context.Background(), ws)
+}
 
-	err := ws.WriteJSON(message)
-	if err != nil {
-		return nil, err
-	}
+// 发送私聊消息
+func SendPrivateMessage(ws *websocket.Conn, user_id qq.UserID, text string) (message_id qq.MessageID, err error) {
+	return SendPrivateMessageContext(context.Background(), ws, user_id, text)
+}
 
-	err = ws.ReadJSON(&data)
-	if err != nil {
-		return nil, err
-	}
+// 发送私聊消息
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SendPrivateMessageContext(ctx context.Context, ws *websocket.Conn, user_id qq.UserID, text string) (message_id qq.MessageID, err error) {
+	return SendTemporaryMessageContext(ctx, ws, user_id, 0, text)
+}
 
-	if data.Code != nil {
-		return nil, fmt.Errorf("%s: %s", *data.Code, *data.Message)
-	}
+// 发送群消息
+func SendGroupMessage(ws *websocket.Conn, group_id qq.GroupID, text string) (message_id qq.MessageID, err error) {
+	return SendGroupMessageContext(context.Background(), ws, group_id, text)
+}
+
+// 发送群消息
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SendGroupMessageContext(ctx context.Context, ws *websocket.Conn, group_id qq.GroupID, text string) (message_id qq.MessageID, err error) {
+	return SendTemporaryMessageContext(ctx, ws, 0, group_id, text)
+}
 
-	return json.Marshal(data.Body)
+// 发送私聊纯文本消息
+//
+// * 消息内容不会被解析为CQ码
+func SendPrivateText(ws *websocket.Conn, user_id qq.UserID, text string) (message_id qq.MessageID, err error) {
+	return SendPrivateTextContext(context.Background(), ws, user_id, text)
 }
 
-// 发送私聊消息
-func SendPrivateMessage(ws *websocket.Conn, user_id uint, text string) (message_id int, err error) {
-	return SendTemporaryMessage(ws, user_id, 0, text)
+// 发送私聊纯文本消息
+//
+// * 消息内容不会被解析为CQ码
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SendPrivateTextContext(ctx context.Context, ws *websocket.Conn, user_id qq.UserID, text string) (message_id qq.MessageID, err error) {
+	return sendMessage(ctx, ws, user_id, 0, text, true)
 }
 
-// 发送群消息
-func SendGroupMessage(ws *websocket.Conn, group_id uint, text string) (message_id int, err error) {
-	return SendTemporaryMessage(ws, 0, group_id, text)
+// 发送群纯文本消息
+//
+// * 消息内容不会被解析为CQ码
+func SendGroupText(ws *websocket.Conn, group_id qq.GroupID, text string) (message_id qq.MessageID, err error) {
+	return SendGroupTextContext(context.Background(), ws, group_id, text)
+}
+
+// 发送群纯文本消息
+//
+// * 消息内容不会被解析为CQ码
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SendGroupTextContext(ctx context.Context, ws *websocket.Conn, group_id qq.GroupID, text string) (message_id qq.MessageID, err error) {
+	return sendMessage(ctx, ws, 0, group_id, text, true)
 }
 
 // 消息ID
 type msg_id struct {
-	ID int `json:"message_id"`
+	ID qq.MessageID `json:"message_id"`
 }
 
 // 发送临时会话消息
-func SendTemporaryMessage(ws *websocket.Conn, user_id, group_id uint, text string) (message_id int, err error) {
+func SendTemporaryMessage(ws *websocket.Conn, user_id qq.UserID, group_id qq.GroupID, text string) (message_id qq.MessageID, err error) {
+	return SendTemporaryMessageContext(context.Background(), ws, user_id, group_id, text)
+}
+
+// 发送临时会话消息
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SendTemporaryMessageContext(ctx context.Context, ws *websocket.Conn, user_id qq.UserID, group_id qq.GroupID, text string) (message_id qq.MessageID, err error) {
+	return sendMessage(ctx, ws, user_id, group_id, text, false)
+}
+
+// 发送消息
+//
+// * auto_escape: 消息内容是否作为纯文本发送
+//
+// * 不支持的消息段将按 Fallback 降级
+func sendMessage(ctx context.Context, ws *websocket.Conn, user_id qq.UserID, group_id qq.GroupID, text string, auto_escape bool) (message_id qq.MessageID, err error) {
 	type params struct {
-		UserID  uint   `json:"user_id"`
-		GroupID uint   `json:"group_id"`
-		Message string `json:"message"`
+		UserID     qq.UserID  `json:"user_id"`
+		GroupID    qq.GroupID `json:"group_id"`
+		Message    string     `json:"message"`
+		AutoEscape bool       `json:"auto_escape"`
 	}
 
-	var msg *msg_id
+	var msg msg_id
+
+	if !auto_escape {
+		text = Degrade(ws, text)
+	}
 
 	var message = ws_data{
 		Action: "send_msg",
-		Params: params{user_id, group_id, text},
+		Params: params{user_id, group_id, text, auto_escape},
 	}
 
-	data, err := message.do(ws)
+	data, err := message.doContext(ctx, ws)
 	if err != nil {
 		return 0, err
 	}
 
-	err = json.Unmarshal(data, &msg)
+	err = schema.Unmarshal(data, &msg)
 	if err != nil {
 		return 0, err
 	}
@@ -88,7 +148,7 @@ func SendTemporaryMessage(ws *websocket.Conn, user_id, group_id uint, text strin
 }
 
 type forward_msg_id struct {
-	ID int `json:"id"`
+	ID qq.MessageID `json:"id"`
 }
 
 // 转发消息ID
@@ -104,34 +164,42 @@ type forward_message_custom struct {
 }
 
 // 自定义转发消息
+//
+// * ID 不为0时为引用已有消息的节点, 其余字段将被忽略
+//
+// * Segments 不为空时代替 Content 以消息段数组发送, Nodes 不为空时作为嵌套的合并转发消息发送
 type ForwardMessage struct {
-	Name    string `json:"name"`
-	Uin     int    `json:"uin"`
-	Content string `json:"content"`
-	Seq     string `json:"seq"`
+	ID       qq.MessageID     `json:"id"`      // 引用的消息ID
+	Name     string           `json:"name"`    // 发送者昵称
+	Uin      qq.UserID        `json:"uin"`     // 发送者QQ号
+	Content  string           `json:"content"` // 消息内容
+	Seq      string           `json:"seq"`     // 消息序列
+	Time     qq.Time          `json:"time"`    // 消息时间, 零值为当前时间
+	Segments cqcode.Message   `json:"-"`       // 消息段数组
+	Nodes    []ForwardMessage `json:"-"`       // 嵌套的合并转发消息
 }
 
 // 发送转发消息数据
-func sendForwardData(ws *websocket.Conn, user_id, group_id uint, v any) (message_id int, err error) {
+func sendForwardData(ctx context.Context, ws *websocket.Conn, user_id qq.UserID, group_id qq.GroupID, v any) (message_id qq.MessageID, err error) {
 	type params struct {
-		UserID   uint `json:"user_id"`
-		GroupID  uint `json:"group_id"`
-		Messages any  `json:"messages"`
+		UserID   qq.UserID  `json:"user_id"`
+		GroupID  qq.GroupID `json:"group_id"`
+		Messages any        `json:"messages"`
 	}
 
-	var msg *msg_id
+	var msg msg_id
 
 	var message = ws_data{
 		Action: "send_forward_msg",
 		Params: params{user_id, group_id, v},
 	}
 
-	data, err := message.do(ws)
+	data, err := message.doContext(ctx, ws)
 	if err != nil {
 		return 0, err
 	}
 
-	err = json.Unmarshal(data, &msg)
+	err = schema.Unmarshal(data, &msg)
 	if err != nil {
 		return 0, err
 	}
@@ -140,7 +208,7 @@ func sendForwardData(ws *websocket.Conn, user_id, group_id uint, v any) (message
 }
 
 // 发送转发消息ID (私聊)
-func SendPrivateForwardMessageID(ws *websocket.Conn, user_id uint, message_ids ...int) (message_id int, err error) {
+func SendPrivateForwardMessageID(ws *websocket.Conn, user_id qq.UserID, message_ids ...qq.MessageID) (message_id qq.MessageID, err error) {
 	var contents []forward_message_id
 
 	for _, mid := range message_ids {
@@ -150,11 +218,11 @@ func SendPrivateForwardMessageID(ws *websocket.Conn, user_id uint, message_ids .
 		})
 	}
 
-	return sendForwardData(ws, user_id, 0, contents)
+	return sendForwardData(context.Background(), ws, user_id, 0, contents)
 }
 
 // 发送自定义转发消息 (私聊)
-func SendPrivateForwardMessageCustom(ws *websocket.Conn, user_id uint, messages []ForwardMessage) (message_id int, err error) {
+func SendPrivateForwardMessageCustom(ws *websocket.Conn, user_id qq.UserID, messages []ForwardMessage) (message_id qq.MessageID, err error) {
 	var contents []forward_message_custom
 
 	for _, message := range messages {
@@ -164,11 +232,11 @@ func SendPrivateForwardMessageCustom(ws *websocket.Conn, user_id uint, messages
 		})
 	}
 
-	return sendForwardData(ws, user_id, 0, contents)
+	return sendForwardData(context.Background(), ws, user_id, 0, contents)
 }
 
 // 发送转发消息ID (群)
-func SendGroupForwardMessageID(ws *websocket.Conn, group_id uint, message_ids ...int) (message_id int, err error) {
+func SendGroupForwardMessageID(ws *websocket.Conn, group_id qq.GroupID, message_ids ...qq.MessageID) (message_id qq.MessageID, err error) {
 	var messages []forward_message_id
 
 	for _, mid := range message_ids {
@@ -178,11 +246,11 @@ func SendGroupForwardMessageID(ws *websocket.Conn, group_id uint, message_ids ..
 		})
 	}
 
-	return sendForwardData(ws, 0, group_id, messages)
+	return sendForwardData(context.Background(), ws, 0, group_id, messages)
 }
 
 // 发送自定义转发消息 (群)
-func SendGroupForwardMessageCustom(ws *websocket.Conn, group_id uint, messages []ForwardMessage) (message_id int, err error) {
+func SendGroupForwardMessageCustom(ws *websocket.Conn, group_id qq.GroupID, messages []ForwardMessage) (message_id qq.MessageID, err error) {
 	var contents []forward_message_custom
 
 	for _, message := range messages {
@@ -192,13 +260,13 @@ func SendGroupForwardMessageCustom(ws *websocket.Conn, group_id uint, messages [
 		})
 	}
 
-	return sendForwardData(ws, 0, group_id, contents)
+	return sendForwardData(context.Background(), ws, 0, group_id, contents)
 }
 
 // 标记消息已读
-func MarkMessageRead(ws *websocket.Conn, message_id int) error {
+func MarkMessageRead(ws *websocket.Conn, message_id qq.MessageID) error {
 	var message = ws_data{
-		Action: "send_forward_msg",
+		Action: "mark_msg_as_read",
 		Params: msg_id{message_id},
 	}
 
@@ -207,49 +275,45 @@ func MarkMessageRead(ws *websocket.Conn, message_id int) error {
 }
 
 // 撤回消息
-func DeleteMessage(ws *websocket.Conn, message_id int) error {
+func DeleteMessage(ws *websocket.Conn, message_id qq.MessageID) error {
+	return DeleteMessageContext(context.Background(), ws, message_id)
+}
+
+// 撤回消息
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func DeleteMessageContext(ctx context.Context, ws *websocket.Conn, message_id qq.MessageID) error {
 	var message = ws_data{
 		Action: "delete_msg",
 		Params: msg_id{message_id},
 	}
 
-	_, err := message.do(ws)
+	_, err := message.doContext(ctx, ws)
 	return err
 }
 
-type sender struct { // 发送人信息
-	Age      int    `json:"age"`      // 年龄
-	Area     string `json:"area"`     // 地区
-	Card     string `json:"card"`     // 群名片／备注
-	Level    string `json:"level"`    // 成员等级
-	Nickname string `json:"nickname"` // 昵称
-	Role     string `json:"role"`     // 角色, owner/admin/member
-	Sex      string `json:"sex"`      // 性别, male/female/unknown
-	Title    string `json:"title"`    // 专属头衔
-	UserID   uint   `json:"user_id"`  // 发送者QQ号
-}
-
-type sender_lite struct {
-	Nickname string `json:"nickname"` // 昵称
-	UserID   uint   `json:"user_id"`  // QQ号
+// 消息发送者
+type MessageSender struct {
+	Nickname string    `json:"nickname"` // 昵称
+	UserID   qq.UserID `json:"user_id"`  // QQ号
 }
 
 // 消息数据
-type message_content struct {
-	Group       bool        `json:"group"`         // 群聊
-	GroupID     uint        `json:"group_id"`      // 群号
-	Message     string      `json:"message"`       // 内容
-	MessageID   int         `json:"message_id"`    // ID
-	MessageIDV2 string      `json:"message_id_v2"` // ID v2
-	MessageSeq  int         `json:"message_seq"`   // 序列
-	MessageType string      `json:"message_type"`  // 类型
-	RealID      int         `json:"real_id"`       // 真实ID
-	Sender      sender_lite `json:"sender"`        // 发送者信息
-	Time        int         `json:"time"`          // 发送消息时的时间戳
+type Message struct {
+	Group       bool          `json:"group"`         // 群聊
+	GroupID     qq.GroupID    `json:"group_id"`      // 群号
+	Message     string        `json:"message"`       // 内容
+	MessageID   qq.MessageID  `json:"message_id"`    // ID
+	MessageIDV2 string        `json:"message_id_v2"` // ID v2
+	MessageSeq  int           `json:"message_seq"`   // 序列
+	MessageType string        `json:"message_type"`  // 类型
+	RealID      qq.MessageID  `json:"real_id"`       // 真实ID
+	Sender      MessageSender `json:"sender"`        // 发送者信息
+	Time        qq.Time       `json:"time"`          // 发送消息时的时间戳
 }
 
 // 获取消息
-func GetMessage(ws *websocket.Conn, message_id int) (content *message_content, err error) {
+func GetMessage(ws *websocket.Conn, message_id qq.MessageID) (content Message, err error) {
 	var message = ws_data{
 		Action: "get_msg",
 		Params: msg_id{message_id},
@@ -257,19 +321,19 @@ func GetMessage(ws *websocket.Conn, message_id int) (content *message_content, e
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return Message{}, err
 	}
 
-	err = json.Unmarshal(data, &content)
+	err = schema.Unmarshal(data, &content)
 	if err != nil {
-		return nil, err
+		return Message{}, err
 	}
 
 	return content, nil
 }
 
 // 图片信息
-type image struct {
+type ImageInfo struct {
 	File     string `json:"file"`     // 图片缓存路径
 	Filename string `json:"filename"` // 图片文件原名
 	Size     int    `json:"size"`     // 图片源文件大小
@@ -277,7 +341,7 @@ type image struct {
 }
 
 // 获取图片信息
-func GetImage(ws *websocket.Conn, file string) (image *image, err error) {
+func GetImage(ws *websocket.Conn, file string) (image ImageInfo, err error) {
 	type params struct {
 		File string `json:"file"`
 	}
@@ -289,23 +353,30 @@ func GetImage(ws *websocket.Conn, file string) (image *image, err error) {
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return ImageInfo{}, err
 	}
 
-	err = json.Unmarshal(data, &image)
+	err = schema.Unmarshal(data, &image)
 	if err != nil {
-		return nil, err
+		return ImageInfo{}, err
 	}
 
 	return image, nil
 }
 
 // 群组踢人
-func SetGroupKick(ws *websocket.Conn, group_id, user_id uint, reject_add_request bool) error {
+func SetGroupKick(ws *websocket.Conn, group_id qq.GroupID, user_id qq.UserID, reject_add_request bool) error {
+	return SetGroupKickContext(context.Background(), ws, group_id, user_id, reject_add_request)
+}
+
+// 群组踢人
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SetGroupKickContext(ctx context.Context, ws *websocket.Conn, group_id qq.GroupID, user_id qq.UserID, reject_add_request bool) error {
 	type params struct {
-		GroupID          uint `json:"group_id"`
-		UserID           uint `json:"user_id"`
-		RejectAddRequest bool `json:"reject_add_request"`
+		GroupID          qq.GroupID `json:"group_id"`
+		UserID           qq.UserID  `json:"user_id"`
+		RejectAddRequest bool       `json:"reject_add_request"`
 	}
 
 	var message = ws_data{
@@ -313,49 +384,78 @@ func SetGroupKick(ws *websocket.Conn, group_id, user_id uint, reject_add_request
 		Params: params{group_id, user_id, reject_add_request},
 	}
 
-	_, err := message.do(ws)
+	_, err := message.doContext(ctx, ws)
 	return err
 }
 
 // 群组单人禁言
-func SetGroupBan(ws *websocket.Conn, group_id, user_id uint, duration uint) error {
+//
+// * duration 精确到秒, 0为取消禁言
+func SetGroupBan(ws *websocket.Conn, group_id qq.GroupID, user_id qq.UserID, duration time.Duration) error {
+	return SetGroupBanContext(context.Background(), ws, group_id, user_id, duration)
+}
+
+// 群组单人禁言
+//
+// * duration 精确到秒, 0为取消禁言
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SetGroupBanContext(ctx context.Context, ws *websocket.Conn, group_id qq.GroupID, user_id qq.UserID, duration time.Duration) error {
 	type params struct {
-		GroupID  uint `json:"group_id"`
-		UserID   uint `json:"user_id"`
-		Duration uint `json:"duration"`
+		GroupID  qq.GroupID  `json:"group_id"`
+		UserID   qq.UserID   `json:"user_id"`
+		Duration qq.Duration `json:"duration"`
 	}
 
 	var message = ws_data{
 		Action: "set_group_ban",
-		Params: params{group_id, user_id, duration},
+		Params: params{group_id, user_id, qq.Duration{Duration: duration}},
 	}
 
-	_, err := message.do(ws)
+	_, err := message.doContext(ctx, ws)
 	return err
 }
 
 // 群组匿名用户禁言
-func SetGroupAnonymousBan(ws *websocket.Conn, group_id uint, flag string, duration uint) error {
+//
+// * duration 精确到秒, 无法取消匿名用户禁言
+func SetGroupAnonymousBan(ws *websocket.Conn, group_id qq.GroupID, flag string, duration time.Duration) error {
+	return SetGroupAnonymousBanContext(context.Background(), ws, group_id, flag, duration)
+}
+
+// 群组匿名用户禁言
+//
+// * duration 精确到秒, 无法取消匿名用户禁言
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SetGroupAnonymousBanContext(ctx context.Context, ws *websocket.Conn, group_id qq.GroupID, flag string, duration time.Duration) error {
 	type params struct {
-		GroupID  uint   `json:"group_id"`
-		Flag     string `json:"flag"`
-		Duration uint   `json:"duration"`
+		GroupID  qq.GroupID  `json:"group_id"`
+		Flag     string      `json:"flag"`
+		Duration qq.Duration `json:"duration"`
 	}
 
 	var message = ws_data{
 		Action: "set_group_anonymous_ban",
-		Params: params{group_id, flag, duration},
+		Params: params{group_id, flag, qq.Duration{Duration: duration}},
 	}
 
-	_, err := message.do(ws)
+	_, err := message.doContext(ctx, ws)
 	return err
 }
 
 // 群组全员禁言
-func SetGroupWholeBan(ws *websocket.Conn, group_id uint, enable bool) error {
+func SetGroupWholeBan(ws *websocket.Conn, group_id qq.GroupID, enable bool) error {
+	return SetGroupWholeBanContext(context.Background(), ws, group_id, enable)
+}
+
+// 群组全员禁言
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SetGroupWholeBanContext(ctx context.Context, ws *websocket.Conn, group_id qq.GroupID, enable bool) error {
 	type params struct {
-		GroupID uint `json:"group_id"`
-		Enable  bool `json:"enable"`
+		GroupID qq.GroupID `json:"group_id"`
+		Enable  bool       `json:"enable"`
 	}
 
 	var message = ws_data{
@@ -363,16 +463,16 @@ func SetGroupWholeBan(ws *websocket.Conn, group_id uint, enable bool) error {
 		Params: params{group_id, enable},
 	}
 
-	_, err := message.do(ws)
+	_, err := message.doContext(ctx, ws)
 	return err
 }
 
 // 群组设置管理员
-func SetGroupAdmin(ws *websocket.Conn, group_id, user_id uint, enable bool) error {
+func SetGroupAdmin(ws *websocket.Conn, group_id qq.GroupID, user_id qq.UserID, enable bool) error {
 	type params struct {
-		GroupID uint `json:"group_id"`
-		UserID  uint `json:"user_id"`
-		Enable  bool `json:"enable"`
+		GroupID qq.GroupID `json:"group_id"`
+		UserID  qq.UserID  `json:"user_id"`
+		Enable  bool       `json:"enable"`
 	}
 
 	var message = ws_data{
@@ -384,15 +484,30 @@ func SetGroupAdmin(ws *websocket.Conn, group_id, user_id uint, enable bool) erro
 	return err
 }
 
-// 该API暂未被go-cqhttp支持
-// TODO: 群组匿名
+// 群组匿名
+//
+// * 该API暂未被go-cqhttp支持, 仅适用于其他OneBot实现
+func SetGroupAnonymous(ws *websocket.Conn, group_id qq.GroupID, enable bool) error {
+	type params struct {
+		GroupID qq.GroupID `json:"group_id"`
+		Enable  bool       `json:"enable"`
+	}
+
+	var message = ws_data{
+		Action: "set_group_anonymous",
+		Params: params{group_id, enable},
+	}
+
+	_, err := message.do(ws)
+	return err
+}
 
 // 设置群名片(群备注)
-func SetGroupCard(ws *websocket.Conn, group_id, user_id uint, card string) error {
+func SetGroupCard(ws *websocket.Conn, group_id qq.GroupID, user_id qq.UserID, card string) error {
 	type params struct {
-		GroupID uint   `json:"group_id"`
-		UserID  uint   `json:"user_id"`
-		Card    string `json:"card"`
+		GroupID qq.GroupID `json:"group_id"`
+		UserID  qq.UserID  `json:"user_id"`
+		Card    string     `json:"card"`
 	}
 
 	var message = ws_data{
@@ -405,10 +520,10 @@ func SetGroupCard(ws *websocket.Conn, group_id, user_id uint, card string) error
 }
 
 // 设置群名
-func SetGroupName(ws *websocket.Conn, group_id uint, name string) error {
+func SetGroupName(ws *websocket.Conn, group_id qq.GroupID, name string) error {
 	type params struct {
-		GroupID uint   `json:"group_id"`
-		Name    string `json:"group_name"`
+		GroupID qq.GroupID `json:"group_id"`
+		Name    string     `json:"group_name"`
 	}
 
 	var message = ws_data{
@@ -421,10 +536,10 @@ func SetGroupName(ws *websocket.Conn, group_id uint, name string) error {
 }
 
 // 退出群组
-func SetGroupLeave(ws *websocket.Conn, group_id uint, dismiss bool) error {
+func SetGroupLeave(ws *websocket.Conn, group_id qq.GroupID, dismiss bool) error {
 	type params struct {
-		GroupID uint `json:"group_id"`
-		Dismiss bool `json:"is_dismiss"`
+		GroupID qq.GroupID `json:"group_id"`
+		Dismiss bool       `json:"is_dismiss"`
 	}
 
 	var message = ws_data{
@@ -437,17 +552,19 @@ func SetGroupLeave(ws *websocket.Conn, group_id uint, dismiss bool) error {
 }
 
 // 设置群组专属头衔
-func SetGroupSpecialTitle(ws *websocket.Conn, group_id, user_id uint, title string, duration uint) error {
+//
+// * duration 精确到秒, 0为永久
+func SetGroupSpecialTitle(ws *websocket.Conn, group_id qq.GroupID, user_id qq.UserID, title string, duration time.Duration) error {
 	type params struct {
-		GroupID      uint   `json:"group_id"`
-		UserID       uint   `json:"user_id"`
-		SpecialTitle string `json:"special_title"`
-		Duration     uint   `json:"duration"`
+		GroupID      qq.GroupID  `json:"group_id"`
+		UserID       qq.UserID   `json:"user_id"`
+		SpecialTitle string      `json:"special_title"`
+		Duration     qq.Duration `json:"duration"`
 	}
 
 	var message = ws_data{
 		Action: "set_group_special_title",
-		Params: params{group_id, user_id, title, duration},
+		Params: params{group_id, user_id, title, qq.Duration{Duration: duration}},
 	}
 
 	_, err := message.do(ws)
@@ -455,9 +572,9 @@ func SetGroupSpecialTitle(ws *websocket.Conn, group_id, user_id uint, title stri
 }
 
 // 群打卡
-func SendGroupSign(ws *websocket.Conn, group_id uint) error {
+func SendGroupSign(ws *websocket.Conn, group_id qq.GroupID) error {
 	type params struct {
-		GroupID uint `json:"group_id"`
+		GroupID qq.GroupID `json:"group_id"`
 	}
 
 	var message = ws_data{
@@ -471,6 +588,13 @@ func SendGroupSign(ws *websocket.Conn, group_id uint) error {
 
 // 处理加好友请求
 func SetFriendAddRequest(ws *websocket.Conn, flag string, approve bool, remark string) error {
+	return SetFriendAddRequestContext(context.Background(), ws, flag, approve, remark)
+}
+
+// 处理加好友请求
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SetFriendAddRequestContext(ctx context.Context, ws *websocket.Conn, flag string, approve bool, remark string) error {
 	type params struct {
 		Flag    string `json:"flag"`
 		Approve bool   `json:"approve"`
@@ -482,12 +606,19 @@ func SetFriendAddRequest(ws *websocket.Conn, flag string, approve bool, remark s
 		Params: params{flag, approve, remark},
 	}
 
-	_, err := message.do(ws)
+	_, err := message.doContext(ctx, ws)
 	return err
 }
 
 // 处理加群请求／邀请
 func SetGroupAddRequest(ws *websocket.Conn, flag, sub_type string, approve bool, remark string) error {
+	return SetGroupAddRequestContext(context.Background(), ws, flag, sub_type, approve, remark)
+}
+
+// 处理加群请求／邀请
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SetGroupAddRequestContext(ctx context.Context, ws *websocket.Conn, flag, sub_type string, approve bool, remark string) error {
 	type params struct {
 		Flag    string `json:"flag"`
 		SubType string `json:"sub_type"`
@@ -500,54 +631,54 @@ func SetGroupAddRequest(ws *websocket.Conn, flag, sub_type string, approve bool,
 		Params: params{flag, sub_type, approve, remark},
 	}
 
-	_, err := message.do(ws)
+	_, err := message.doContext(ctx, ws)
 	return err
 }
 
 // 登录信息
-type login struct {
-	Nickname string `json:"nickname"` // 昵称
-	UserID   uint   `json:"user_id"`  // QQ号
+type LoginInfo struct {
+	Nickname string    `json:"nickname"` // 昵称
+	UserID   qq.UserID `json:"user_id"`  // QQ号
 }
 
 // 获取登录号信息
-func GetLoginInfo(ws *websocket.Conn) (login *login, err error) {
+func GetLoginInfo(ws *websocket.Conn) (login LoginInfo, err error) {
 	var message = ws_data{Action: "get_login_info"}
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return LoginInfo{}, err
 	}
 
-	err = json.Unmarshal(data, &login)
+	err = schema.Unmarshal(data, &login)
 	if err != nil {
-		return nil, err
+		return LoginInfo{}, err
 	}
 
 	return login, err
 }
 
 // 企点账号信息
-type qidian struct {
-	MasterID   uint   `json:"master_id"`   // 父账号ID
-	ExtName    string `json:"ext_name"`    // 用户昵称
-	CreateTime int    `json:"create_time"` // 账号创建时间
+type QidianAccountInfo struct {
+	MasterID   qq.UserID `json:"master_id"`   // 父账号ID
+	ExtName    string    `json:"ext_name"`    // 用户昵称
+	CreateTime qq.Time   `json:"create_time"` // 账号创建时间
 }
 
 // 获取企点账号信息
 //
 // * 该API只有企点协议可用
-func GetQidianAccountInfo(ws *websocket.Conn) (qidian *qidian, err error) {
+func GetQidianAccountInfo(ws *websocket.Conn) (qidian QidianAccountInfo, err error) {
 	var message = ws_data{Action: "qidian_get_account_info"}
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return QidianAccountInfo{}, err
 	}
 
-	err = json.Unmarshal(data, &qidian)
+	err = schema.Unmarshal(data, &qidian)
 	if err != nil {
-		return nil, err
+		return QidianAccountInfo{}, err
 	}
 
 	return qidian, err
@@ -575,20 +706,20 @@ func SetProfile(ws *websocket.Conn, nickname, company, email, college, personal_
 }
 
 // 陌生人信息
-type stranger struct {
-	Age       int    `json:"age"`        // 年龄
-	Level     int    `json:"level"`      // 等级
-	LoginDays int    `json:"login_days"` // QQ达人
-	Nickname  string `json:"nickname"`   // 昵称
-	Qid       string `json:"qid"`        // QQ ID身份卡
-	Sex       string `json:"sex"`        // 性别, male/female/unknown
-	UserID    uint   `json:"user_id"`    // QQ号
+type StrangerInfo struct {
+	Age       int       `json:"age"`        // 年龄
+	Level     int       `json:"level"`      // 等级
+	LoginDays int       `json:"login_days"` // QQ达人
+	Nickname  string    `json:"nickname"`   // 昵称
+	Qid       string    `json:"qid"`        // QQ ID身份卡
+	Sex       string    `json:"sex"`        // 性别, male/female/unknown
+	UserID    qq.UserID `json:"user_id"`    // QQ号
 }
 
 // 获取陌生人信息
-func GetStrangerInfo(ws *websocket.Conn, user_id uint) (stranger *stranger, err error) {
+func GetStrangerInfo(ws *websocket.Conn, user_id qq.UserID) (stranger StrangerInfo, err error) {
 	type params struct {
-		UserID uint `json:"user_id"`
+		UserID qq.UserID `json:"user_id"`
 	}
 
 	var message = ws_data{
@@ -598,26 +729,26 @@ func GetStrangerInfo(ws *websocket.Conn, user_id uint) (stranger *stranger, err
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return StrangerInfo{}, err
 	}
 
-	err = json.Unmarshal(data, &stranger)
+	err = schema.Unmarshal(data, &stranger)
 	if err != nil {
-		return nil, err
+		return StrangerInfo{}, err
 	}
 
 	return stranger, err
 }
 
 // 好友信息
-type friend struct {
-	Nickname string `json:"nickname"` // 昵称
-	Remark   string `json:"remark"`   // 备注名
-	UserID   uint   `json:"user_id"`  // QQ号
+type Friend struct {
+	Nickname string    `json:"nickname"` // 昵称
+	Remark   string    `json:"remark"`   // 备注名
+	UserID   qq.UserID `json:"user_id"`  // QQ号
 }
 
 // 获取好友列表
-func GetFriendList(ws *websocket.Conn) (list *[]friend, err error) {
+func GetFriendList(ws *websocket.Conn) (list []Friend, err error) {
 	var message = ws_data{
 		Action: "get_friend_list",
 	}
@@ -627,7 +758,38 @@ func GetFriendList(ws *websocket.Conn) (list *[]friend, err error) {
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &list)
+	err = schema.Unmarshal(data, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+// 好友分组
+type FriendCategory struct {
+	CategoryID      int      `json:"categoryId"`      // 分组ID
+	CategorySortID  int      `json:"categorySortId"`  // 分组排序ID
+	CategoryName    string   `json:"categoryName"`    // 分组名称
+	CategoryMbCount int      `json:"categoryMbCount"` // 分组内好友数量
+	OnlineCount     int      `json:"onlineCount"`     // 在线好友数量
+	BuddyList       []Friend `json:"buddyList"`       // 分组内的好友
+}
+
+// 获取按分组排列的好友列表
+//
+// * go-cqhttp 没有好友分组相关的API, 该API来自 NapCat 和 LLOneBot 的扩展, 仅适用于这些实现
+func GetFriendsWithCategory(ws *websocket.Conn) (list []FriendCategory, err error) {
+	var message = ws_data{
+		Action: "get_friends_with_category",
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.Unmarshal(data, &list)
 	if err != nil {
 		return nil, err
 	}
@@ -636,14 +798,14 @@ func GetFriendList(ws *websocket.Conn) (list *[]friend, err error) {
 }
 
 // 单向好友信息
-type unidirectional_friend struct {
-	Nickname string `json:"nickname"` // 昵称
-	UserID   uint   `json:"user_id"`  // QQ号
-	Source   string `json:"source"`   // 添加途径
+type UnidirectionalFriend struct {
+	Nickname string    `json:"nickname"` // 昵称
+	UserID   qq.UserID `json:"user_id"`  // QQ号
+	Source   string    `json:"source"`   // 添加途径
 }
 
 // 获取单向好友列表
-func GetUnidirectionalFriendList(ws *websocket.Conn) (list *[]unidirectional_friend, err error) {
+func GetUnidirectionalFriendList(ws *websocket.Conn) (list []UnidirectionalFriend, err error) {
 	var message = ws_data{Action: "get_unidirectional_friend_list"}
 
 	data, err := message.do(ws)
@@ -651,7 +813,7 @@ func GetUnidirectionalFriendList(ws *websocket.Conn) (list *[]unidirectional_fri
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &list)
+	err = schema.Unmarshal(data, &list)
 	if err != nil {
 		return nil, err
 	}
@@ -660,9 +822,9 @@ func GetUnidirectionalFriendList(ws *websocket.Conn) (list *[]unidirectional_fri
 }
 
 // 删除单向好友
-func DeleteUnidirectionalFriend(ws *websocket.Conn, user_id uint) error {
+func DeleteUnidirectionalFriend(ws *websocket.Conn, user_id qq.UserID) error {
 	type params struct {
-		UserID uint `json:"user_id"`
+		UserID qq.UserID `json:"user_id"`
 	}
 
 	var message = ws_data{
@@ -675,9 +837,9 @@ func DeleteUnidirectionalFriend(ws *websocket.Conn, user_id uint) error {
 }
 
 // 删除好友
-func DeleteFriend(ws *websocket.Conn, friend_id uint) error {
+func DeleteFriend(ws *websocket.Conn, friend_id qq.UserID) error {
 	type params struct {
-		FriendID uint `json:"friend_id"`
+		FriendID qq.UserID `json:"friend_id"`
 	}
 
 	var message = ws_data{
@@ -690,22 +852,22 @@ func DeleteFriend(ws *websocket.Conn, friend_id uint) error {
 }
 
 // 群信息
-type group struct {
-	GroupCreateTime int    `json:"group_create_time"` // 群创建时间
-	GroupID         uint   `json:"group_id"`          // 群号
-	GroupLevel      int    `json:"group_level"`       // 群等级
-	GroupMemo       string `json:"group_memo"`        // 群备注
-	GroupName       string `json:"group_name"`        // 群名称
-	MaxMemberCount  uint   `json:"max_member_count"`  // 最大成员数
-	MemberCount     uint   `json:"member_count"`      // 成员数
+type Group struct {
+	GroupCreateTime qq.Time    `json:"group_create_time"` // 群创建时间
+	GroupID         qq.GroupID `json:"group_id"`          // 群号
+	GroupLevel      int        `json:"group_level"`       // 群等级
+	GroupMemo       string     `json:"group_memo"`        // 群备注
+	GroupName       string     `json:"group_name"`        // 群名称
+	MaxMemberCount  uint       `json:"max_member_count"`  // 最大成员数
+	MemberCount     uint       `json:"member_count"`      // 成员数
 }
 
 // 获取群信息
 //
 // * 如果机器人尚未加入群, group_create_time, group_level, max_member_count 和 member_count 将会为0
-func GetGroupInfo(ws *websocket.Conn, group_id uint) (info *group, err error) {
+func GetGroupInfo(ws *websocket.Conn, group_id qq.GroupID) (info Group, err error) {
 	type params struct {
-		GroupID uint `json:"group_id"`
+		GroupID qq.GroupID `json:"group_id"`
 	}
 
 	var message = ws_data{
@@ -715,19 +877,19 @@ func GetGroupInfo(ws *websocket.Conn, group_id uint) (info *group, err error) {
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return Group{}, err
 	}
 
-	err = json.Unmarshal(data, &info)
+	err = schema.Unmarshal(data, &info)
 	if err != nil {
-		return nil, err
+		return Group{}, err
 	}
 
 	return info, err
 }
 
 // 获取群列表
-func GetGroupList(ws *websocket.Conn) (list *[]group, err error) {
+func GetGroupList(ws *websocket.Conn) (list []Group, err error) {
 	var message = ws_data{Action: "get_group_list"}
 
 	data, err := message.do(ws)
@@ -735,7 +897,7 @@ func GetGroupList(ws *websocket.Conn) (list *[]group, err error) {
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &list)
+	err = schema.Unmarshal(data, &list)
 	if err != nil {
 		return nil, err
 	}
@@ -744,30 +906,30 @@ func GetGroupList(ws *websocket.Conn) (list *[]group, err error) {
 }
 
 // 获取群成员信息
-type group_member struct {
-	Age             uint   `json:"age"`               // 年龄
-	Area            string `json:"area"`              // 地区
-	Card            string `json:"card"`              // 群名片／备注
-	CardChangeable  bool   `json:"card_changeable"`   // 是否允许修改群名片
-	GroupID         uint   `json:"group_id"`          // 群号
-	JoinTime        int    `json:"join_time"`         // 加群时间戳
-	LastSentTime    int    `json:"last_sent_time"`    // 最后发言时间戳
-	Level           string `json:"level"`             // 成员等级
-	Nickname        string `json:"nickname"`          // 昵称
-	Role            string `json:"role"`              // 角色, owner/admin/member
-	Sex             string `json:"sex"`               // 性别
-	ShutUpTimestamp uint   `json:"shut_up_timestamp"` // 禁言到期时间
-	Title           string `json:"title"`             // 专属头衔
-	TitleExpireTime uint   `json:"title_expire_time"` // 专属头衔过期时间戳
-	Unfriendly      bool   `json:"unfriendly"`        // 是否不良记录成员
-	UserID          uint   `json:"user_id"`           // QQ号
+type GroupMember struct {
+	Age             uint       `json:"age"`               // 年龄
+	Area            string     `json:"area"`              // 地区
+	Card            string     `json:"card"`              // 群名片／备注
+	CardChangeable  bool       `json:"card_changeable"`   // 是否允许修改群名片
+	GroupID         qq.GroupID `json:"group_id"`          // 群号
+	JoinTime        qq.Time    `json:"join_time"`         // 加群时间戳
+	LastSentTime    qq.Time    `json:"last_sent_time"`    // 最后发言时间戳
+	Level           string     `json:"level"`             // 成员等级
+	Nickname        string     `json:"nickname"`          // 昵称
+	Role            string     `json:"role"`              // 角色, owner/admin/member
+	Sex             string     `json:"sex"`               // 性别
+	ShutUpTimestamp qq.Time    `json:"shut_up_timestamp"` // 禁言到期时间
+	Title           string     `json:"title"`             // 专属头衔
+	TitleExpireTime qq.Time    `json:"title_expire_time"` // 专属头衔过期时间戳
+	Unfriendly      bool       `json:"unfriendly"`        // 是否不良记录成员
+	UserID          qq.UserID  `json:"user_id"`           // QQ号
 }
 
 // 获取群成员信息
-func GetGroupMemberInfo(ws *websocket.Conn, group_id, user_id uint) (info *group_member, err error) {
+func GetGroupMemberInfo(ws *websocket.Conn, group_id qq.GroupID, user_id qq.UserID) (info GroupMember, err error) {
 	type params struct {
-		GroupID uint `json:"group_id"`
-		UserID  uint `json:"user_id"`
+		GroupID qq.GroupID `json:"group_id"`
+		UserID  qq.UserID  `json:"user_id"`
 	}
 
 	var message = ws_data{
@@ -777,21 +939,21 @@ func GetGroupMemberInfo(ws *websocket.Conn, group_id, user_id uint) (info *group
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return GroupMember{}, err
 	}
 
-	err = json.Unmarshal(data, &info)
+	err = schema.Unmarshal(data, &info)
 	if err != nil {
-		return nil, err
+		return GroupMember{}, err
 	}
 
 	return info, err
 }
 
 // 获取群成员列表
-func GetGroupMemberList(ws *websocket.Conn, group_id uint) (list *[]group_member, err error) {
+func GetGroupMemberList(ws *websocket.Conn, group_id qq.GroupID) (list []GroupMember, err error) {
 	type params struct {
-		GroupID uint `json:"group_id"`
+		GroupID qq.GroupID `json:"group_id"`
 	}
 
 	var message = ws_data{
@@ -804,7 +966,7 @@ func GetGroupMemberList(ws *websocket.Conn, group_id uint) (list *[]group_member
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &list)
+	err = schema.Unmarshal(data, &list)
 	if err != nil {
 		return nil, err
 	}
@@ -813,31 +975,74 @@ func GetGroupMemberList(ws *websocket.Conn, group_id uint) (list *[]group_member
 }
 
 // 群荣誉列表
-type honor_list struct {
-	GroupID          uint          `json:"group_id"`           // 群号
-	CurrentTalkative group_honor   `json:"current_talkative"`  // 当前龙王
-	EmotionList      []group_honor `json:"emotion_list"`       // 快乐之源
-	LegendList       []group_honor `json:"legend_list"`        // 群聊炽焰
-	PerformerLis     []group_honor `json:"performer_lis"`      // 群聊之火
-	StrongNewbieList []group_honor `json:"strong_newbie_list"` // 冒尖小春笋
-	TalkativeList    []group_honor `json:"talkative_list"`     // 历史龙王
+type GroupHonorInfo struct {
+	GroupID          qq.GroupID   `json:"group_id"`           // 群号
+	CurrentTalkative Talkative    `json:"current_talkative"`  // 当前龙王
+	EmotionList      []GroupHonor `json:"emotion_list"`       // 快乐之源
+	LegendList       []GroupHonor `json:"legend_list"`        // 群聊炽焰
+	PerformerList    []GroupHonor `json:"performer_list"`     // 群聊之火
+	StrongNewbieList []GroupHonor `json:"strong_newbie_list"` // 冒尖小春笋
+	TalkativeList    []GroupHonor `json:"talkative_list"`     // 历史龙王
 }
 
 // 群荣誉
-type group_honor struct {
-	Avatar      string `json:"avatar"`      // 头像URL
-	Description string `json:"description"` // 荣誉描述
-	Nickname    string `json:"nickname"`    // 昵称
-	UserID      uint   `json:"user_id"`     // QQ号
+//
+// * go-cqhttp 的荣誉列表不包含获得时间, 持续时间只以文本形式出现在 Description 中
+type GroupHonor struct {
+	HonorType   qq.HonorType `json:"-"`           // 荣誉类型
+	Avatar      string       `json:"avatar"`      // 头像URL
+	Description string       `json:"description"` // 荣誉描述
+	Nickname    string       `json:"nickname"`    // 昵称
+	UserID      qq.UserID    `json:"user_id"`     // QQ号
+}
+
+// 当前龙王
+type Talkative struct {
+	Avatar   string    `json:"avatar"`    // 头像URL
+	DayCount int       `json:"day_count"` // 持续天数
+	Nickname string    `json:"nickname"`  // 昵称
+	UserID   qq.UserID `json:"user_id"`   // QQ号
+	Since    qq.Time   `json:"-"`         // 成为龙王的时间, 根据持续天数推算, 精确到天
+}
+
+// 根据持续天数推算成为龙王的时间
+func (talkative *Talkative) since(now time.Time) {
+	if talkative.UserID == 0 {
+		return
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	talkative.Since = qq.Time{Time: today.AddDate(0, 0, 1-talkative.DayCount)}
+}
+
+// 获取指定类型的群荣誉列表
+//
+// * HonorTalkative 返回历史龙王
+func (info GroupHonorInfo) List(types qq.HonorType) []GroupHonor {
+	switch types {
+	case qq.HonorTalkative:
+		return info.TalkativeList
+	case qq.HonorPerformer:
+		return info.PerformerList
+	case qq.HonorLegend:
+		return info.LegendList
+	case qq.HonorStrongNewbie:
+		return info.StrongNewbieList
+	case qq.HonorEmotion:
+		return info.EmotionList
+	}
+
+	return nil
 }
 
 // 获取群荣誉信息
 //
-// * type: 要获取的群荣誉类型, talkative, performer, legend, strong_newbie emotion, 以分别获取单个类型的群荣誉数据, 或传入all获取所有数据
-func GetGroupHonorInfo(ws *websocket.Conn, group_id uint, types string) (honor *honor_list, err error) {
+// * types: 要获取的群荣誉类型, 未获取的类型对应的字段为空, qq.HonorAll 获取所有类型
+func GetGroupHonorInfo(ws *websocket.Conn, group_id qq.GroupID, types qq.HonorType) (honor GroupHonorInfo, err error) {
 	type params struct {
-		GroupID uint   `json:"group_id"`
-		Type    string `json:"type"`
+		GroupID qq.GroupID   `json:"group_id"`
+		Type    qq.HonorType `json:"type"`
 	}
 
 	var message = ws_data{
@@ -847,22 +1052,156 @@ func GetGroupHonorInfo(ws *websocket.Conn, group_id uint, types string) (honor *
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return GroupHonorInfo{}, err
 	}
 
-	err = json.Unmarshal(data, &honor)
+	err = schema.Unmarshal(data, &honor)
 	if err != nil {
-		return nil, err
+		return GroupHonorInfo{}, err
+	}
+
+	honor.CurrentTalkative.since(time.Now())
+	for _, types := range []qq.HonorType{qq.HonorTalkative, qq.HonorPerformer, qq.HonorLegend, qq.HonorStrongNewbie, qq.HonorEmotion} {
+		list := honor.List(types)
+		for i := range list {
+			list[i].HonorType = types
+		}
 	}
 
 	return honor, err
 }
 
-// 该API暂未被go-cqhttp支持
-// TODO: 获取Cookies
-// TODO: 获取CSRF Token
-// TODO: 获取QQ相关接口凭证
-// TODO: 获取语音
+// 获取单个类型的群荣誉列表
+func GetGroupHonorList(ws *websocket.Conn, group_id qq.GroupID, types qq.HonorType) (list []GroupHonor, err error) {
+	info, err := GetGroupHonorInfo(ws, group_id, types)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.List(types), nil
+}
+
+// QQ相关接口凭证
+type Credentials struct {
+	Cookies   string `json:"cookies"`    // Cookies
+	CSRFToken int    `json:"csrf_token"` // CSRF Token
+}
+
+// 获取Cookies
+//
+// * 可选参数: domain
+//
+// * 该API暂未被go-cqhttp支持, 仅适用于其他OneBot实现
+func GetCookies(ws *websocket.Conn, domain string) (cookies string, err error) {
+	type params struct {
+		Domain string `json:"domain,omitempty"`
+	}
+
+	var message = ws_data{
+		Action: "get_cookies",
+		Params: params{domain},
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return "", err
+	}
+
+	var credentials Credentials
+
+	err = schema.Unmarshal(data, &credentials)
+	if err != nil {
+		return "", err
+	}
+
+	return credentials.Cookies, nil
+}
+
+// 获取CSRF Token
+//
+// * 该API暂未被go-cqhttp支持, 仅适用于其他OneBot实现
+func GetCSRFToken(ws *websocket.Conn) (token int, err error) {
+	var message = ws_data{Action: "get_csrf_token"}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return 0, err
+	}
+
+	var csrf struct {
+		Token int `json:"token"`
+	}
+
+	err = schema.Unmarshal(data, &csrf)
+	if err != nil {
+		return 0, err
+	}
+
+	return csrf.Token, nil
+}
+
+// 获取QQ相关接口凭证
+//
+// * 可选参数: domain
+//
+// * 该API暂未被go-cqhttp支持, 仅适用于其他OneBot实现
+func GetCredentials(ws *websocket.Conn, domain string) (credentials Credentials, err error) {
+	type params struct {
+		Domain string `json:"domain,omitempty"`
+	}
+
+	var message = ws_data{
+		Action: "get_credentials",
+		Params: params{domain},
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	err = schema.Unmarshal(data, &credentials)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	return credentials, err
+}
+
+// 获取语音
+//
+// * file: 收到的语音文件名, 即消息段中的 file 参数
+//
+// * out_format: 要转换到的格式, 支持 mp3 amr wma m4a spx ogg wav flac
+//
+// * 返回转换后的语音文件路径
+//
+// * 该API暂未被go-cqhttp支持, 仅适用于其他OneBot实现
+func GetRecord(ws *websocket.Conn, file, out_format string) (path string, err error) {
+	type params struct {
+		File      string `json:"file"`
+		OutFormat string `json:"out_format"`
+	}
+
+	var message = ws_data{
+		Action: "get_record",
+		Params: params{file, out_format},
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return "", err
+	}
+
+	var record filepath
+
+	err = schema.Unmarshal(data, &record)
+	if err != nil {
+		return "", err
+	}
+
+	return record.Path, nil
+}
 
 // 是否能发送图片或语音
 type can_send_image_or_record struct {
@@ -871,7 +1210,7 @@ type can_send_image_or_record struct {
 
 // 检查是否可以发送图片
 func CanSendImage(ws *websocket.Conn) (status bool, err error) {
-	var send *can_send_image_or_record
+	var send can_send_image_or_record
 
 	message := ws_data{
 		Action: "can_send_image",
@@ -882,7 +1221,7 @@ func CanSendImage(ws *websocket.Conn) (status bool, err error) {
 		return false, err
 	}
 
-	err = json.Unmarshal(data, &send)
+	err = schema.Unmarshal(data, &send)
 	if err != nil {
 		return false, err
 	}
@@ -892,7 +1231,7 @@ func CanSendImage(ws *websocket.Conn) (status bool, err error) {
 
 // 检查是否可以发送语音
 func CanSendRecord(ws *websocket.Conn) (status bool, err error) {
-	var send *can_send_image_or_record
+	var send can_send_image_or_record
 
 	message := ws_data{
 		Action: "can_send_record",
@@ -903,7 +1242,7 @@ func CanSendRecord(ws *websocket.Conn) (status bool, err error) {
 		return false, err
 	}
 
-	err = json.Unmarshal(data, &send)
+	err = schema.Unmarshal(data, &send)
 	if err != nil {
 		return false, err
 	}
@@ -912,50 +1251,84 @@ func CanSendRecord(ws *websocket.Conn) (status bool, err error) {
 }
 
 // 版本
-type version struct {
-	AppFullName     string `json:"app_full_name"`    // 应用完整名称
-	AppName         string `json:"app_name"`         // 应用标识, 固定值
-	AppVersion      string `json:"app_version"`      // 应用版本
-	CoolqDirectory  string `json:"coolq_directory"`  // 原CoolQ运行目录
-	Protocol        int    `json:"protocol"`         // 登陆使用协议类型
-	ProtocolVersion string `json:"protocol_version"` // OneBot标准版本, 固定值
-	RuntimeOS       string `json:"runtime_os"`       // 运行时操作系统
-	RuntimeVersion  string `json:"runtime_version"`  // 运行时版本
-	Version         string `json:"version"`          // 应用版本
+type VersionInfo struct {
+	AppFullName     string `json:"app_full_name"`           // 应用完整名称
+	AppName         string `json:"app_name"`                // 应用标识, 固定值
+	AppVersion      string `json:"app_version"`             // 应用版本
+	CoolqDirectory  string `json:"coolq_directory"`         // 原CoolQ运行目录
+	Protocol        int    `json:"protocol,omitempty"`      // 登陆使用协议类型
+	ProtocolName    int    `json:"protocol_name,omitempty"` // 登陆使用协议类型, go-cqhttp v1.x 使用该字段名
+	ProtocolVersion string `json:"protocol_version"`        // OneBot标准版本, 固定值
+	RuntimeOS       string `json:"runtime_os"`              // 运行时操作系统
+	RuntimeVersion  string `json:"runtime_version"`         // 运行时版本
+	Version         string `json:"version"`                 // 应用版本
 }
 
 // 获取版本信息
-func GetVersionInfo(ws *websocket.Conn) (ver *version, err error) {
+func GetVersionInfo(ws *websocket.Conn) (ver VersionInfo, err error) {
 	var message = ws_data{Action: "get_version_info"}
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return VersionInfo{}, err
 	}
 
-	err = json.Unmarshal(data, &ver)
+	err = schema.Unmarshal(data, &ver)
 	if err != nil {
-		return nil, err
+		return VersionInfo{}, err
+	}
+
+	// 旧版本使用 protocol, v1.x 使用 protocol_name
+	if ver.Protocol == 0 {
+		ver.Protocol = ver.ProtocolName
+	} else if ver.ProtocolName == 0 {
+		ver.ProtocolName = ver.Protocol
 	}
 
 	return ver, err
 }
 
-// 该API暂未被go-cqhttp支持
-// TODO: 清理缓存
+// 重启 go-cqhttp
+//
+// * delay: 延迟重启的时间
+//
+// * 该API自go-cqhttp 1.0.0起已被移除, 仅适用于其他OneBot实现
+func SetRestart(ws *websocket.Conn, delay time.Duration) error {
+	type params struct {
+		Delay int64 `json:"delay"`
+	}
+
+	var message = ws_data{
+		Action: "set_restart",
+		Params: params{delay.Milliseconds()},
+	}
+
+	_, err := message.do(ws)
+	return err
+}
+
+// 清理缓存
+//
+// * 该API暂未被go-cqhttp支持, 仅适用于其他OneBot实现
+func CleanCache(ws *websocket.Conn) error {
+	var message = ws_data{Action: "clean_cache"}
+
+	_, err := message.do(ws)
+	return err
+}
 
 // 获取群头像URL
-func GetGroupAvatarURL(group_id uint) (url string) {
+func GetGroupAvatarURL(group_id qq.GroupID) (url string) {
 	return fmt.Sprint("https://p.qlogo.cn/gh/", group_id, "/", group_id, "/100")
 }
 
 // 设置群头像
 //
 // * 目前这个API在登录一段时间后因cookie失效而失效, 请考虑后使用
-func SetGroupPortrait(ws *websocket.Conn, group_id uint, file string) error {
+func SetGroupPortrait(ws *websocket.Conn, group_id qq.GroupID, file string) error {
 	type params struct {
-		GroupID uint   `json:"group_id"`
-		File    string `json:"file"`
+		GroupID qq.GroupID `json:"group_id"`
+		File    string     `json:"file"`
 	}
 
 	var message = ws_data{
@@ -978,7 +1351,7 @@ func GetWordSlices(ws *websocket.Conn, text string) (slice []string, err error)
 		Content string `json:"content"`
 	}
 
-	var word *word
+	var word word
 
 	message := ws_data{
 		Action: ".get_word_slices",
@@ -990,7 +1363,7 @@ func GetWordSlices(ws *websocket.Conn, text string) (slice []string, err error)
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &word)
+	err = schema.Unmarshal(data, &word)
 	if err != nil {
 		return nil, err
 	}
@@ -999,24 +1372,26 @@ func GetWordSlices(ws *websocket.Conn, text string) (slice []string, err error)
 }
 
 // OCR
-type ocr struct {
-	Texts    []text `json:"texts"`    // 文本
-	Language string `json:"language"` // 语言
+type OCR struct {
+	Texts    []OCRText `json:"texts"`    // 文本
+	Language string    `json:"language"` // 语言
 }
 
-type text struct {
-	Text        string        `json:"text"` // 置信度
-	Confidence  int           `json:"confidence"`
-	Coordinates []coordinates `json:"coordinates"` // 坐标
+// OCR文本
+type OCRText struct {
+	Text        string       `json:"text"`        // 文本
+	Confidence  int          `json:"confidence"`  // 置信度
+	Coordinates []Coordinate `json:"coordinates"` // 坐标
 }
 
-type coordinates struct {
+// 坐标
+type Coordinate struct {
 	X int `json:"x"`
 	Y int `json:"y"`
 }
 
 // 图片OCR
-func OcrImage(ws *websocket.Conn, image string) (ocr *ocr, err error) {
+func OcrImage(ws *websocket.Conn, image string) (ocr OCR, err error) {
 	type params struct {
 		Image string `json:"image"`
 	}
@@ -1028,58 +1403,58 @@ func OcrImage(ws *websocket.Conn, image string) (ocr *ocr, err error) {
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return OCR{}, err
 	}
 
-	err = json.Unmarshal(data, &ocr)
+	err = schema.Unmarshal(data, &ocr)
 	if err != nil {
-		return nil, err
+		return OCR{}, err
 	}
 
 	return ocr, err
 }
 
 // 群系統消息
-type group_system_msg struct {
-	InvitedRequest *[]invited_request `json:"invited_requests,omitempty"` // 邀请消息列表
-	JoinRequest    *[]join_request    `json:"join_requests,omitempty"`    // 进群消息列表
+type GroupSystemMessage struct {
+	InvitedRequest []InvitedRequest `json:"invited_requests,omitempty"` // 邀请消息列表
+	JoinRequest    []JoinRequest    `json:"join_requests,omitempty"`    // 进群消息列表
 }
 
 // 邀请消息列表
-type invited_request struct {
-	Actor         uint   `json:"actor"`          // 处理者, 未处理为0
-	Checked       bool   `json:"checked"`        // 是否已被处理
-	GroupID       uint   `json:"group_id"`       // 群号
-	GroupName     string `json:"group_name"`     // 群名
-	RequestID     int    `json:"request_id"`     // 请求ID
-	RequesterNick string `json:"requester_nick"` // 请求者昵称
-	RequesterUin  uint   `json:"requester_uin"`  // 请求者ID
+type InvitedRequest struct {
+	Actor         qq.UserID  `json:"actor"`          // 处理者, 未处理为0
+	Checked       bool       `json:"checked"`        // 是否已被处理
+	GroupID       qq.GroupID `json:"group_id"`       // 群号
+	GroupName     string     `json:"group_name"`     // 群名
+	RequestID     int        `json:"request_id"`     // 请求ID
+	RequesterNick string     `json:"requester_nick"` // 请求者昵称
+	RequesterUin  qq.UserID  `json:"requester_uin"`  // 请求者ID
 }
 
 // 进群消息列表
-type join_request struct {
-	Actor         uint   `json:"actor"`          // 处理者, 未处理为0
-	Checked       bool   `json:"checked"`        // 是否已被处理
-	GroupID       uint   `json:"group_id"`       // 群号
-	GroupName     string `json:"group_name"`     // 群名
-	Message       string `json:"message"`        // 验证消息
-	RequestID     int    `json:"request_id"`     // 请求ID
-	RequesterNick string `json:"requester_nick"` // 请求者昵称
-	RequesterUin  uint   `json:"requester_uin"`  // 请求者ID
+type JoinRequest struct {
+	Actor         qq.UserID  `json:"actor"`          // 处理者, 未处理为0
+	Checked       bool       `json:"checked"`        // 是否已被处理
+	GroupID       qq.GroupID `json:"group_id"`       // 群号
+	GroupName     string     `json:"group_name"`     // 群名
+	Message       string     `json:"message"`        // 验证消息
+	RequestID     int        `json:"request_id"`     // 请求ID
+	RequesterNick string     `json:"requester_nick"` // 请求者昵称
+	RequesterUin  qq.UserID  `json:"requester_uin"`  // 请求者ID
 }
 
 // 获取群系统消息
-func GetGroupSystemMessage(ws *websocket.Conn) (system_message *group_system_msg, err error) {
+func GetGroupSystemMessage(ws *websocket.Conn) (system_message GroupSystemMessage, err error) {
 	var message = ws_data{Action: "get_group_system_msg"}
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return GroupSystemMessage{}, err
 	}
 
-	err = json.Unmarshal(data, &system_message)
+	err = schema.Unmarshal(data, &system_message)
 	if err != nil {
-		return nil, err
+		return GroupSystemMessage{}, err
 	}
 
 	return system_message, err
@@ -1088,11 +1463,11 @@ func GetGroupSystemMessage(ws *websocket.Conn) (system_message *group_system_msg
 // 上传私聊文件
 //
 // * 只能上传本地文件, 需要上传 http 文件的话请先调用DownloadFile()下载
-func UploadPrivateFile(ws *websocket.Conn, user_id uint, file, name string) error {
+func UploadPrivateFile(ws *websocket.Conn, user_id qq.UserID, file, name string) error {
 	type params struct {
-		UserID uint   `json:"user_id"`
-		File   string `json:"file"`
-		Name   string `json:"name"`
+		UserID qq.UserID `json:"user_id"`
+		File   string    `json:"file"`
+		Name   string    `json:"name"`
 	}
 
 	var message = ws_data{
@@ -1109,12 +1484,12 @@ func UploadPrivateFile(ws *websocket.Conn, user_id uint, file, name string) erro
 // * 在不提供 folder 参数的情况下默认上传到根目录
 //
 // * 只能上传本地文件, 需要上传 http 文件的话请先调用DownloadFile()下载
-func UploadGroupFile(ws *websocket.Conn, group_id uint, file, name, folder string) error {
+func UploadGroupFile(ws *websocket.Conn, group_id qq.GroupID, file, name, folder string) error {
 	type params struct {
-		GroupID uint   `json:"group_id"`
-		File    string `json:"file"`
-		Name    string `json:"name"`
-		Folder  string `json:"folder"`
+		GroupID qq.GroupID `json:"group_id"`
+		File    string     `json:"file"`
+		Name    string     `json:"name"`
+		Folder  string     `json:"folder"`
 	}
 
 	var message = ws_data{
@@ -1127,18 +1502,18 @@ func UploadGroupFile(ws *websocket.Conn, group_id uint, file, name, folder strin
 }
 
 // 群文件系统信息
-type group_file_system struct {
-	FileCount  uint `json:"file_count"`  // 文件总数
-	LimitCount uint `json:"limit_count"` // 文件上限
-	UsedSpace  uint `json:"used_space"`  // 已使用空间, 单位byte
-	TotalSpace uint `json:"total_space"` // 空间上限, 单位byte
-	GroupID    uint `json:"group_id"`    // 群号
+type GroupFileSystemInfo struct {
+	FileCount  uint       `json:"file_count"`  // 文件总数
+	LimitCount uint       `json:"limit_count"` // 文件上限
+	UsedSpace  uint       `json:"used_space"`  // 已使用空间, 单位byte
+	TotalSpace uint       `json:"total_space"` // 空间上限, 单位byte
+	GroupID    qq.GroupID `json:"group_id"`    // 群号
 }
 
 // 获取群文件系统信息
-func GetGroupFileSystemInfo(ws *websocket.Conn, group_id uint) (file *group_file_system, err error) {
+func GetGroupFileSystemInfo(ws *websocket.Conn, group_id qq.GroupID) (info GroupFileSystemInfo, err error) {
 	type params struct {
-		GroupID uint `json:"group_id"`
+		GroupID qq.GroupID `json:"group_id"`
 	}
 
 	var message = ws_data{
@@ -1148,53 +1523,53 @@ func GetGroupFileSystemInfo(ws *websocket.Conn, group_id uint) (file *group_file
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return GroupFileSystemInfo{}, err
 	}
 
-	err = json.Unmarshal(data, &file)
+	err = schema.Unmarshal(data, &info)
 	if err != nil {
-		return nil, err
+		return GroupFileSystemInfo{}, err
 	}
 
-	return file, err
+	return info, err
 }
 
 // 群根目录文件列表
-type group_files struct {
-	Files   *[]file   `json:"files"`   // 文件列表
-	Folders *[]folder `json:"folders"` // 文件夹列表
+type GroupFiles struct {
+	Files   []File   `json:"files"`   // 文件列表
+	Folders []Folder `json:"folders"` // 文件夹列表
 }
 
 // 文件
-type file struct {
-	GroupID       uint   `json:"group_id"`       // 群号
-	FileID        string `json:"file_id"`        // 文件ID
-	FileName      string `json:"file_name"`      // 文件名
-	Busid         int    `json:"busid"`          // 文件类型
-	FileSize      uint   `json:"file_size"`      // 文件大小, 单位byte
-	UploadTime    uint   `json:"upload_time"`    // 上传时间
-	DeadTime      uint   `json:"dead_time"`      // 过期时间, 永久文件为0
-	ModifyTime    int    `json:"modify_time"`    // 最后修改时间
-	DownloadTimes int    `json:"download_times"` // 下载次数
-	Uploader      uint   `json:"uploader"`       // 上传者ID
-	UploaderName  string `json:"uploader_name"`  // 上传者名字
+type File struct {
+	GroupID       qq.GroupID `json:"group_id"`       // 群号
+	FileID        string     `json:"file_id"`        // 文件ID
+	FileName      string     `json:"file_name"`      // 文件名
+	Busid         int        `json:"busid"`          // 文件类型
+	FileSize      uint       `json:"file_size"`      // 文件大小, 单位byte
+	UploadTime    qq.Time    `json:"upload_time"`    // 上传时间
+	DeadTime      qq.Time    `json:"dead_time"`      // 过期时间, 永久文件为0
+	ModifyTime    qq.Time    `json:"modify_time"`    // 最后修改时间
+	DownloadTimes int        `json:"download_times"` // 下载次数
+	Uploader      qq.UserID  `json:"uploader"`       // 上传者ID
+	UploaderName  string     `json:"uploader_name"`  // 上传者名字
 }
 
 // 文件夹
-type folder struct {
-	GroupID        uint   `json:"group_id"`         // 群号
-	FolderID       string `json:"folder_id"`        // 文件夹ID
-	FolderName     string `json:"folder_name"`      // 文件名
-	CreateTime     int    `json:"create_time"`      // 创建时间
-	Creator        uint   `json:"creator"`          // 创建者
-	CreatorName    string `json:"creator_name"`     // 创建者名字
-	TotalFileCount uint   `json:"total_file_count"` // 子文件数量
+type Folder struct {
+	GroupID        qq.GroupID `json:"group_id"`         // 群号
+	FolderID       string     `json:"folder_id"`        // 文件夹ID
+	FolderName     string     `json:"folder_name"`      // 文件名
+	CreateTime     qq.Time    `json:"create_time"`      // 创建时间
+	Creator        qq.UserID  `json:"creator"`          // 创建者
+	CreatorName    string     `json:"creator_name"`     // 创建者名字
+	TotalFileCount uint       `json:"total_file_count"` // 子文件数量
 }
 
 // 获取群根目录文件列表
-func GetGroupRootFiles(ws *websocket.Conn, group_id uint) (file *group_files, err error) {
+func GetGroupRootFiles(ws *websocket.Conn, group_id qq.GroupID) (files GroupFiles, err error) {
 	type params struct {
-		GroupID uint `json:"group_id"`
+		GroupID qq.GroupID `json:"group_id"`
 	}
 
 	var message = ws_data{
@@ -1204,22 +1579,22 @@ func GetGroupRootFiles(ws *websocket.Conn, group_id uint) (file *group_files, er
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return GroupFiles{}, err
 	}
 
-	err = json.Unmarshal(data, &file)
+	err = schema.Unmarshal(data, &files)
 	if err != nil {
-		return nil, err
+		return GroupFiles{}, err
 	}
 
-	return file, err
+	return files, err
 }
 
 // 获取群子目录文件列表
-func GetGroupFilesByFolder(ws *websocket.Conn, group_id uint, folder_id string) (file *group_files, err error) {
+func GetGroupFilesByFolder(ws *websocket.Conn, group_id qq.GroupID, folder_id string) (files GroupFiles, err error) {
 	type params struct {
-		GroupID  uint   `json:"group_id"`
-		FolderID string `json:"folder_id"`
+		GroupID  qq.GroupID `json:"group_id"`
+		FolderID string     `json:"folder_id"`
 	}
 
 	var message = ws_data{
@@ -1229,23 +1604,23 @@ func GetGroupFilesByFolder(ws *websocket.Conn, group_id uint, folder_id string)
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return GroupFiles{}, err
 	}
 
-	err = json.Unmarshal(data, &file)
+	err = schema.Unmarshal(data, &files)
 	if err != nil {
-		return nil, err
+		return GroupFiles{}, err
 	}
 
-	return file, err
+	return files, err
 }
 
 // 创建群文件文件夹
-func CreateGroupFileFolder(ws *websocket.Conn, group_id uint, name string) error {
+func CreateGroupFileFolder(ws *websocket.Conn, group_id qq.GroupID, name string) error {
 	type params struct {
-		GroupID  uint   `json:"group_id"`
-		Name     string `json:"name"`
-		ParentID string `json:"parent_id"`
+		GroupID  qq.GroupID `json:"group_id"`
+		Name     string     `json:"name"`
+		ParentID string     `json:"parent_id"`
 	}
 
 	var message = ws_data{
@@ -1258,10 +1633,10 @@ func CreateGroupFileFolder(ws *websocket.Conn, group_id uint, name string) error
 }
 
 // 删除群文件文件夹
-func DeleteGroupFolder(ws *websocket.Conn, group_id uint, folder_id string) error {
+func DeleteGroupFolder(ws *websocket.Conn, group_id qq.GroupID, folder_id string) error {
 	type params struct {
-		GroupID  uint   `json:"group_id"`
-		FolderID string `json:"folder_id"`
+		GroupID  qq.GroupID `json:"group_id"`
+		FolderID string     `json:"folder_id"`
 	}
 
 	var message = ws_data{
@@ -1274,11 +1649,11 @@ func DeleteGroupFolder(ws *websocket.Conn, group_id uint, folder_id string) erro
 }
 
 // 删除群文件
-func DeleteGroupFile(ws *websocket.Conn, group_id uint, file_id string, busid int) error {
+func DeleteGroupFile(ws *websocket.Conn, group_id qq.GroupID, file_id string, busid int) error {
 	type params struct {
-		GroupID uint   `json:"group_id"`
-		FileID  string `json:"file_id"`
-		BusID   int    `json:"busid"`
+		GroupID qq.GroupID `json:"group_id"`
+		FileID  string     `json:"file_id"`
+		BusID   int        `json:"busid"`
 	}
 
 	var message = ws_data{
@@ -1296,14 +1671,14 @@ type group_file struct {
 }
 
 // 获取群文件资源链接
-func GetGroupFileURL(ws *websocket.Conn, group_id uint, file_id string, busid int) (URL string, err error) {
+func GetGroupFileURL(ws *websocket.Conn, group_id qq.GroupID, file_id string, busid int) (URL string, err error) {
 	type params struct {
-		GroupID uint   `json:"group_id"`
-		FileID  string `json:"file_id"`
-		BusID   int    `json:"busid"`
+		GroupID qq.GroupID `json:"group_id"`
+		FileID  string     `json:"file_id"`
+		BusID   int        `json:"busid"`
 	}
 
-	var file *group_file
+	var file group_file
 
 	message := ws_data{
 		Action: "get_group_file_url",
@@ -1315,7 +1690,7 @@ func GetGroupFileURL(ws *websocket.Conn, group_id uint, file_id string, busid in
 		return "", err
 	}
 
-	err = json.Unmarshal(data, &file)
+	err = schema.Unmarshal(data, &file)
 	if err != nil {
 		return "", err
 	}
@@ -1323,51 +1698,36 @@ func GetGroupFileURL(ws *websocket.Conn, group_id uint, file_id string, busid in
 	return file.URL, err
 }
 
-// 状态
-type status struct {
-	Online bool     `json:"online"` // 表示BOT是否在线
-	More   struct { // 运行统计
-		DisconnectTime  int `json:"DisconnectTimes"` // TCP链接断开次数
-		LastMessageTime int `json:"LastMessageTime"` // 最后一次发送消息的时间戳
-		LostTime        int `json:"LostTimes"`       // 账号掉线次数
-		MessageReceived int `json:"MessageReceived"` // 接受信息总数
-		MessageSent     int `json:"MessageSent"`     // 发送信息总数
-		PacketLost      int `json:"PacketLost"`      // 数据包丢失总数
-		PacketReceived  int `json:"PacketReceived"`  // 收到的数据包总数
-		PacketSent      int `json:"PacketSent"`      // 发送的数据包总数
-	} `json:"stat"`
-}
-
 // 获取状态
-func GetStatus(ws *websocket.Conn) (status *status, err error) {
+func GetStatus(ws *websocket.Conn) (status event.Status, err error) {
 	var message = ws_data{
 		Action: "get_status",
 	}
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return event.Status{}, err
 	}
 
-	err = json.Unmarshal(data, &status)
+	err = schema.Unmarshal(data, &status)
 	if err != nil {
-		return nil, err
+		return event.Status{}, err
 	}
 
 	return status, err
 }
 
 // @全体成员
-type at_all struct {
+type AtAllRemain struct {
 	CanAtAll                 bool `json:"can_at_all"`                    // 是否可以@全体成员
 	RemainAtAllCountForGroup int  `json:"remain_at_all_count_for_group"` // 群内所有管理当天剩余@全体成员次数
 	RemainAtAllCountForUin   int  `json:"remain_at_all_count_for_uin"`   // Bot 当天剩余@全体成员次数
 }
 
 // 获取群@全体成员剩余次数
-func GetGroupAtAllRemain(ws *websocket.Conn, group_id uint) (at *at_all, err error) {
+func GetGroupAtAllRemain(ws *websocket.Conn, group_id qq.GroupID) (at AtAllRemain, err error) {
 	type params struct {
-		GroupID uint `json:"group_id"`
+		GroupID qq.GroupID `json:"group_id"`
 	}
 
 	var message = ws_data{
@@ -1377,40 +1737,45 @@ func GetGroupAtAllRemain(ws *websocket.Conn, group_id uint) (at *at_all, err err
 
 	data, err := message.do(ws)
 	if err != nil {
-		return nil, err
+		return AtAllRemain{}, err
 	}
 
-	err = json.Unmarshal(data, &at)
+	err = schema.Unmarshal(data, &at)
 	if err != nil {
-		return nil, err
+		return AtAllRemain{}, err
 	}
 
 	return at, err
 }
 
 // 群公告
-type group_notice struct {
-	Message struct {
-		Images *[]group_notice_image `json:"images"` // 图片
-		Text   string                `json:"text"`   // 公告内容
-	} `json:"message"`
-	PublishTime int  `json:"publish_time"` // 发送时间
-	SenderID    uint `json:"sender_id"`    // 操作者
+type GroupNotice struct {
+	NoticeID    string             `json:"notice_id"`    // 公告ID
+	Message     GroupNoticeMessage `json:"message"`      // 公告内容
+	PublishTime qq.Time            `json:"publish_time"` // 发送时间
+	SenderID    qq.UserID          `json:"sender_id"`    // 操作者
+}
+
+// 群公告图片
+// 群公告内容
+type GroupNoticeMessage struct {
+	Images []GroupNoticeImage `json:"images"` // 图片
+	Text   string             `json:"text"`   // 公告内容
 }
 
 // 群公告图片
-type group_notice_image struct {
+type GroupNoticeImage struct {
 	ID     string `json:"id"`     // 图片ID
 	Height string `json:"height"` // 高
 	Width  string `json:"width"`  // 宽
 }
 
 // 发送群公告
-func SendGroupNotice(ws *websocket.Conn, group_id uint, content, image string) error {
+func SendGroupNotice(ws *websocket.Conn, group_id qq.GroupID, content, image string) error {
 	type params struct {
-		GroupID uint   `json:"group_id"`
-		Content string `json:"content"`
-		Image   string `json:"image"`
+		GroupID qq.GroupID `json:"group_id"`
+		Content string     `json:"content"`
+		Image   string     `json:"image"`
 	}
 
 	var message = ws_data{
@@ -1423,9 +1788,9 @@ func SendGroupNotice(ws *websocket.Conn, group_id uint, content, image string) e
 }
 
 // 获取群公告
-func GetGroupNotice(ws *websocket.Conn, group_id uint) (notice *[]group_notice, err error) {
+func GetGroupNotice(ws *websocket.Conn, group_id qq.GroupID) (notice []GroupNotice, err error) {
 	type params struct {
-		GroupID uint `json:"group_id"`
+		GroupID qq.GroupID `json:"group_id"`
 	}
 
 	var message = ws_data{
@@ -1438,7 +1803,7 @@ func GetGroupNotice(ws *websocket.Conn, group_id uint) (notice *[]group_notice,
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &notice)
+	err = schema.Unmarshal(data, &notice)
 	if err != nil {
 		return nil, err
 	}
@@ -1446,6 +1811,22 @@ func GetGroupNotice(ws *websocket.Conn, group_id uint) (notice *[]group_notice,
 	return notice, err
 }
 
+// 删除群公告
+func DeleteGroupNotice(ws *websocket.Conn, group_id qq.GroupID, notice_id string) error {
+	type params struct {
+		GroupID  qq.GroupID `json:"group_id"`
+		NoticeID string     `json:"notice_id"`
+	}
+
+	var message = ws_data{
+		Action: "_del_group_notice",
+		Params: params{group_id, notice_id},
+	}
+
+	_, err := message.do(ws)
+	return err
+}
+
 // 重载事件过滤器
 func ReloadEventFilter(ws *websocket.Conn, file string) error {
 	type params struct {
@@ -1480,7 +1861,7 @@ func DownloadFile(ws *websocket.Conn, url string, header []string, thread_count
 		Header      []string `json:"headers"`
 	}
 
-	var download *filepath
+	var download filepath
 
 	message := ws_data{
 		Action: "download_file",
@@ -1492,7 +1873,7 @@ func DownloadFile(ws *websocket.Conn, url string, header []string, thread_count
 		return "", err
 	}
 
-	err = json.Unmarshal(data, &download)
+	err = schema.Unmarshal(data, &download)
 	if err != nil {
 		return "", err
 	}
@@ -1502,17 +1883,11 @@ func DownloadFile(ws *websocket.Conn, url string, header []string, thread_count
 
 // 在线设备
 type online struct {
-	Clients []client `json:"clients"` // 在线客户端列表
-}
-
-type client struct {
-	AppID      int    `json:"app_id"`      // 客户端ID
-	DeviceKind string `json:"device_kind"` // 设备类型
-	DeviceName string `json:"device_name"` // 设备名称
+	Clients []event.Client `json:"clients"` // 在线客户端列表
 }
 
 // 获取当前账号在线客户端列表
-func GetOnlineClients(ws *websocket.Conn) (client *online, err error) {
+func GetOnlineClients(ws *websocket.Conn) (clients []event.Client, err error) {
 	var message = ws_data{Action: "get_online_clients"}
 
 	data, err := message.do(ws)
@@ -1520,50 +1895,26 @@ func GetOnlineClients(ws *websocket.Conn) (client *online, err error) {
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &client)
+	var online online
+
+	err = schema.Unmarshal(data, &online)
 	if err != nil {
 		return nil, err
 	}
 
-	return client, err
+	return online.Clients, err
 }
 
 // 群消息历史记录
 type history_message struct {
-	Messages []group_message `json:"messages"` // 从起始序号开始的前19条消息
-}
-
-// 群消息
-type group_message struct {
-	// 匿名信息, 如果不是匿名消息则为null
-	Anonymous   anonymous `json:"anonymous"`
-	Font        int       `json:"font"`         // 字体
-	Time        int       `json:"time"`         // 事件发生的时间戳
-	SelfID      uint      `json:"self_id"`      // 收到事件的机器人QQ号
-	PostType    string    `json:"post_type"`    // 上报类型
-	MessageType string    `json:"message_type"` // 消息类型
-	SubType     string    `json:"sub_type"`     // 消息子类型, 正常消息是normal, 匿名消息是anonymous, 系统提示(如「管理员已禁止群内匿名聊天」)是notice
-	MessageID   int       `json:"message_id"`   // 消息ID
-	GroupID     uint      `json:"group_id"`     // 群号
-	UserID      uint      `json:"user_id"`      // 发送者QQ号
-	Message     string    `json:"message"`      // 消息内容
-	RawMessage  string    `json:"raw_message"`  // 原始消息内容
-	MessageSeq  int       `json:"message_seq"`  // 消息序列
-	Sender      sender    `json:"sender"`
-}
-
-// 匿名信息
-type anonymous struct {
-	ID   int    `json:"id"`   // 匿名用户ID
-	Name string `json:"name"` // 匿名用户名称
-	Flag string `json:"flag"` // 匿名用户flag, 在调用禁言API时需要传入
+	Messages []event.GroupMessage `json:"messages"` // 从起始序号开始的前19条消息
 }
 
 // 获取群消息历史记录
-func GetGroupMessageHistory(ws *websocket.Conn, message_seq, group_id uint) (history *history_message, err error) {
+func GetGroupMessageHistory(ws *websocket.Conn, message_seq uint, group_id qq.GroupID) (messages []event.GroupMessage, err error) {
 	type params struct {
-		Seq     uint `json:"message_seq"`
-		GroupID uint `json:"group_id"`
+		Seq     uint       `json:"message_seq"`
+		GroupID qq.GroupID `json:"group_id"`
 	}
 
 	var message = ws_data{
@@ -1576,29 +1927,31 @@ func GetGroupMessageHistory(ws *websocket.Conn, message_seq, group_id uint) (his
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &history)
+	var history history_message
+
+	err = schema.Unmarshal(data, &history)
 	if err != nil {
 		return nil, err
 	}
 
-	return history, err
+	return history.Messages, err
 }
 
 // 精华消息
-type essence struct {
-	MessageID    int    `json:"message_id"`    // 消息ID
-	OperatorID   uint   `json:"operator_id"`   // 操作者QQ号
-	OperatorNick string `json:"operator_nick"` // 操作者昵称
-	OperatorTime int    `json:"operator_time"` // 精华设置时间
-	SenderID     uint   `json:"sender_id"`     // 发送者QQ号
-	SenderNick   string `json:"sender_nick"`   // 发送者昵称
-	SenderTime   int    `json:"sender_time"`   // 消息发送时间
+type EssenceMessage struct {
+	MessageID    qq.MessageID `json:"message_id"`    // 消息ID
+	OperatorID   qq.UserID    `json:"operator_id"`   // 操作者QQ号
+	OperatorNick string       `json:"operator_nick"` // 操作者昵称
+	OperatorTime qq.Time      `json:"operator_time"` // 精华设置时间
+	SenderID     qq.UserID    `json:"sender_id"`     // 发送者QQ号
+	SenderNick   string       `json:"sender_nick"`   // 发送者昵称
+	SenderTime   qq.Time      `json:"sender_time"`   // 消息发送时间
 }
 
 // 设置精华消息
-func SetEssenceMessage(ws *websocket.Conn, message_id int) error {
+func SetEssenceMessage(ws *websocket.Conn, message_id qq.MessageID) error {
 	type params struct {
-		MessageID int `json:"message_id"`
+		MessageID qq.MessageID `json:"message_id"`
 	}
 
 	var message = ws_data{
@@ -1611,9 +1964,9 @@ func SetEssenceMessage(ws *websocket.Conn, message_id int) error {
 }
 
 // 移出精华消息
-func DeleteEssenceMessage(ws *websocket.Conn, message_id int) error {
+func DeleteEssenceMessage(ws *websocket.Conn, message_id qq.MessageID) error {
 	type params struct {
-		MessageID int `json:"message_id"`
+		MessageID qq.MessageID `json:"message_id"`
 	}
 
 	var message = ws_data{
@@ -1626,9 +1979,9 @@ func DeleteEssenceMessage(ws *websocket.Conn, message_id int) error {
 }
 
 // 获取精华消息列表
-func GetEssenceMessageList(ws *websocket.Conn, group_id uint) (essence *[]essence, err error) {
+func GetEssenceMessageList(ws *websocket.Conn, group_id qq.GroupID) (list []EssenceMessage, err error) {
 	type params struct {
-		GroupID uint `json:"group_id"`
+		GroupID qq.GroupID `json:"group_id"`
 	}
 
 	var message = ws_data{
@@ -1641,12 +1994,12 @@ func GetEssenceMessageList(ws *websocket.Conn, group_id uint) (essence *[]essenc
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &essence)
+	err = schema.Unmarshal(data, &list)
 	if err != nil {
 		return nil, err
 	}
 
-	return essence, err
+	return list, err
 }
 
 type url_safely struct {
@@ -1661,7 +2014,7 @@ func CheckURLSafely(ws *websocket.Conn, url string) (level int, err error) {
 		URL string `json:"url"`
 	}
 
-	var url_safely *url_safely
+	var url_safely url_safely
 
 	var message = ws_data{
 		Action: "check_url_safely",
@@ -1673,7 +2026,7 @@ func CheckURLSafely(ws *websocket.Conn, url string) (level int, err error) {
 		return 0, err
 	}
 
-	err = json.Unmarshal(data, &url_safely)
+	err = schema.Unmarshal(data, &url_safely)
 	if err != nil {
 		return 0, err
 	}
@@ -1682,16 +2035,17 @@ func CheckURLSafely(ws *websocket.Conn, url string) (level int, err error) {
 }
 
 type model struct {
-	Variants []variant `json:"variants"`
+	Variants []ModelVariant `json:"variants"`
 }
 
-type variant struct {
+// 在线机型
+type ModelVariant struct {
 	ModelShow string `json:"model_show"` // 显示机型
 	NeedPay   bool   `json:"need_pay"`   // 是否需要会员
 }
 
 // 获取在线机型
-func GetModelShow(ws *websocket.Conn, content string) (model *model, err error) {
+func GetModelShow(ws *websocket.Conn, content string) (variants []ModelVariant, err error) {
 	type params struct {
 		Model string `json:"model"`
 	}
@@ -1706,12 +2060,14 @@ func GetModelShow(ws *websocket.Conn, content string) (model *model, err error)
 		return nil, err
 	}
 
-	err = json.Unmarshal(data, &model)
+	var model model
+
+	err = schema.Unmarshal(data, &model)
 	if err != nil {
 		return nil, err
 	}
 
-	return model, err
+	return model.Variants, err
 }
 
 // 设置在线机型
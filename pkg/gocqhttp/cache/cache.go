@@ -0,0 +1,463 @@
+// 群, 群成员和好友信息缓存
+//
+// * 通过 Handle 接收事件, 根据群成员变动, 管理员变动, 名片和头衔变更以及好友添加更新缓存
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"koi/pkg/gocqhttp"
+	"koi/pkg/gocqhttp/event"
+	"koi/pkg/gocqhttp/qq"
+
+	"github.com/gorilla/websocket"
+)
+
+// 群缓存
+type group struct {
+	info       gocqhttp.Group
+	info_at    time.Time // 群信息获取时间, 零值表示需要重新获取
+	members    map[qq.UserID]gocqhttp.GroupMember
+	members_at time.Time               // 成员列表获取时间, 零值表示尚未获取完整列表
+	member_at  map[qq.UserID]time.Time // 单独获取的成员的获取时间
+}
+
+// 缓存
+type Cache struct {
+	ws  *websocket.Conn
+	ttl time.Duration
+
+	mu         sync.RWMutex
+	groups     map[qq.GroupID]*group
+	groups_at  time.Time // 群列表获取时间
+	friends    map[qq.UserID]gocqhttp.Friend
+	friends_at time.Time // 好友列表获取时间
+}
+
+// 创建缓存
+//
+// * ttl: 缓存有效期, 过期后在下次查询时重新获取, 0为永不过期
+func New(ws *websocket.Conn, ttl time.Duration) *Cache {
+	return &Cache{
+		ws:      ws,
+		ttl:     ttl,
+		groups:  make(map[qq.GroupID]*group),
+		friends: make(map[qq.UserID]gocqhttp.Friend),
+	}
+}
+
+// 是否已过期
+func (cache *Cache) expired(at time.Time) bool {
+	return at.IsZero() || (cache.ttl > 0 && time.Since(at) > cache.ttl)
+}
+
+func (cache *Cache) group(group_id qq.GroupID) *group {
+	g := cache.groups[group_id]
+	if g == nil {
+		g = &group{
+			members:   make(map[qq.UserID]gocqhttp.GroupMember),
+			member_at: make(map[qq.UserID]time.Time),
+		}
+		cache.groups[group_id] = g
+	}
+
+	return g
+}
+
+// 预热缓存
+//
+// * 获取好友列表, 群列表和所有群的成员列表
+func (cache *Cache) Warm() error {
+	err := cache.RefreshFriends()
+	if err != nil {
+		return err
+	}
+
+	err = cache.RefreshGroups()
+	if err != nil {
+		return err
+	}
+
+	cache.mu.RLock()
+	ids := make([]qq.GroupID, 0, len(cache.groups))
+	for id := range cache.groups {
+		ids = append(ids, id)
+	}
+	cache.mu.RUnlock()
+
+	for _, id := range ids {
+		err = cache.RefreshMembers(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 重新获取群列表
+func (cache *Cache) RefreshGroups() error {
+	list, err := gocqhttp.GetGroupList(cache.ws)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	exists := make(map[qq.GroupID]bool, len(list))
+
+	for _, info := range list {
+		g := cache.group(info.GroupID)
+		g.info = info
+		g.info_at = now
+		exists[info.GroupID] = true
+	}
+
+	// 移除已经退出的群
+	for id := range cache.groups {
+		if !exists[id] {
+			delete(cache.groups, id)
+		}
+	}
+
+	cache.groups_at = now
+
+	return nil
+}
+
+// 重新获取群成员列表
+func (cache *Cache) RefreshMembers(group_id qq.GroupID) error {
+	list, err := gocqhttp.GetGroupMemberList(cache.ws, group_id)
+	if err != nil {
+		return err
+	}
+
+	members := make(map[qq.UserID]gocqhttp.GroupMember, len(list))
+	for _, member := range list {
+		members[member.UserID] = member
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	g := cache.group(group_id)
+	g.members = members
+	g.members_at = time.Now()
+	g.member_at = make(map[qq.UserID]time.Time)
+
+	return nil
+}
+
+// 重新获取好友列表
+func (cache *Cache) RefreshFriends() error {
+	list, err := gocqhttp.GetFriendList(cache.ws)
+	if err != nil {
+		return err
+	}
+
+	friends := make(map[qq.UserID]gocqhttp.Friend, len(list))
+	for _, friend := range list {
+		friends[friend.UserID] = friend
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.friends = friends
+	cache.friends_at = time.Now()
+
+	return nil
+}
+
+// 清空缓存
+//
+// * 之后的查询将重新获取
+func (cache *Cache) Invalidate() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.groups = make(map[qq.GroupID]*group)
+	cache.groups_at = time.Time{}
+	cache.friends = make(map[qq.UserID]gocqhttp.Friend)
+	cache.friends_at = time.Time{}
+}
+
+// 获取群列表
+func (cache *Cache) Groups() ([]gocqhttp.Group, error) {
+	cache.mu.RLock()
+	expired := cache.expired(cache.groups_at)
+	cache.mu.RUnlock()
+
+	if expired {
+		err := cache.RefreshGroups()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	list := make([]gocqhttp.Group, 0, len(cache.groups))
+	for _, g := range cache.groups {
+		if !g.info_at.IsZero() {
+			list = append(list, g.info)
+		}
+	}
+
+	return list, nil
+}
+
+// 获取群信息
+func (cache *Cache) Group(group_id qq.GroupID) (gocqhttp.Group, error) {
+	cache.mu.RLock()
+	g, ok := cache.groups[group_id]
+	if ok && !cache.expired(g.info_at) {
+		info := g.info
+		cache.mu.RUnlock()
+		return info, nil
+	}
+	cache.mu.RUnlock()
+
+	info, err := gocqhttp.GetGroupInfo(cache.ws, group_id)
+	if err != nil {
+		return gocqhttp.Group{}, err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	g = cache.group(group_id)
+	g.info = info
+	g.info_at = time.Now()
+
+	return info, nil
+}
+
+// 获取群成员列表
+func (cache *Cache) Members(group_id qq.GroupID) ([]gocqhttp.GroupMember, error) {
+	cache.mu.RLock()
+	g, ok := cache.groups[group_id]
+	expired := !ok || cache.expired(g.members_at)
+	cache.mu.RUnlock()
+
+	if expired {
+		err := cache.RefreshMembers(group_id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	g, ok = cache.groups[group_id]
+	if !ok {
+		return nil, nil
+	}
+
+	list := make([]gocqhttp.GroupMember, 0, len(g.members))
+	for _, member := range g.members {
+		list = append(list, member)
+	}
+
+	return list, nil
+}
+
+// 获取群成员信息
+//
+// * 缓存中没有该成员或已过期时单独获取
+func (cache *Cache) Member(group_id qq.GroupID, user_id qq.UserID) (gocqhttp.GroupMember, error) {
+	cache.mu.RLock()
+	if g, ok := cache.groups[group_id]; ok {
+		if member, ok := g.members[user_id]; ok && !cache.expired(g.memberAt(user_id)) {
+			cache.mu.RUnlock()
+			return member, nil
+		}
+	}
+	cache.mu.RUnlock()
+
+	return cache.fetchMember(group_id, user_id)
+}
+
+func (cache *Cache) fetchMember(group_id qq.GroupID, user_id qq.UserID) (gocqhttp.GroupMember, error) {
+	member, err := gocqhttp.GetGroupMemberInfo(cache.ws, group_id, user_id)
+	if err != nil {
+		return gocqhttp.GroupMember{}, err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	g := cache.group(group_id)
+	g.members[user_id] = member
+	g.member_at[user_id] = time.Now()
+
+	return member, nil
+}
+
+// 群成员的获取时间
+//
+// * 取成员列表和单独获取中较新的时间
+func (g *group) memberAt(user_id qq.UserID) time.Time {
+	if at, ok := g.member_at[user_id]; ok && at.After(g.members_at) {
+		return at
+	}
+
+	return g.members_at
+}
+
+// 获取好友列表
+func (cache *Cache) Friends() ([]gocqhttp.Friend, error) {
+	cache.mu.RLock()
+	expired := cache.expired(cache.friends_at)
+	cache.mu.RUnlock()
+
+	if expired {
+		err := cache.RefreshFriends()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	list := make([]gocqhttp.Friend, 0, len(cache.friends))
+	for _, friend := range cache.friends {
+		list = append(list, friend)
+	}
+
+	return list, nil
+}
+
+// 获取好友信息
+//
+// * ok: 是否为好友
+func (cache *Cache) Friend(user_id qq.UserID) (friend gocqhttp.Friend, ok bool, err error) {
+	cache.mu.RLock()
+	expired := cache.expired(cache.friends_at)
+	cache.mu.RUnlock()
+
+	if expired {
+		err := cache.RefreshFriends()
+		if err != nil {
+			return gocqhttp.Friend{}, false, err
+		}
+	}
+
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	friend, ok = cache.friends[user_id]
+
+	return friend, ok, nil
+}
+
+// 处理事件
+//
+// * 接收 go-cqhttp 上报的原始事件, 与缓存无关的事件将被忽略
+//
+// * 新成员和新好友的信息会立即获取
+func (cache *Cache) Handle(data []byte) error {
+	e, _, err := event.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	switch e := e.(type) {
+	case *event.GroupIncrease:
+		if e.UserID == e.SelfID {
+			cache.mu.Lock()
+			cache.groups_at = time.Time{}
+			cache.mu.Unlock()
+
+			_, err = cache.Group(e.GroupID)
+			return err
+		}
+
+		cache.mu.Lock()
+		if g, ok := cache.groups[e.GroupID]; ok && !g.info_at.IsZero() {
+			g.info.MemberCount++
+		}
+		cache.mu.Unlock()
+
+		_, err = cache.fetchMember(e.GroupID, e.UserID)
+		return err
+	case *event.GroupDecrease:
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+
+		if e.UserID == e.SelfID || e.SubType == "kick_me" {
+			delete(cache.groups, e.GroupID)
+			return nil
+		}
+
+		if g, ok := cache.groups[e.GroupID]; ok {
+			delete(g.members, e.UserID)
+			delete(g.member_at, e.UserID)
+
+			if !g.info_at.IsZero() && g.info.MemberCount > 0 {
+				g.info.MemberCount--
+			}
+		}
+	case *event.GroupAdmin:
+		cache.update(e.GroupID, e.UserID, func(member *gocqhttp.GroupMember) {
+			if e.SubType == "set" {
+				member.Role = "admin"
+			} else {
+				member.Role = "member"
+			}
+		})
+	case *event.GroupCard:
+		cache.update(e.GroupID, e.UserID, func(member *gocqhttp.GroupMember) {
+			member.Card = e.NewCard
+		})
+	case *event.Title:
+		cache.update(e.GroupID, e.UserID, func(member *gocqhttp.GroupMember) {
+			member.Title = e.Title
+		})
+	case *event.FriendAdd:
+		stranger, err := gocqhttp.GetStrangerInfo(cache.ws, e.UserID)
+		if err != nil {
+			// 无法获取资料时在下次查询时重新获取好友列表
+			cache.mu.Lock()
+			cache.friends_at = time.Time{}
+			cache.mu.Unlock()
+
+			return err
+		}
+
+		cache.mu.Lock()
+		cache.friends[e.UserID] = gocqhttp.Friend{
+			Nickname: stranger.Nickname,
+			UserID:   e.UserID,
+		}
+		cache.mu.Unlock()
+	}
+
+	return nil
+}
+
+// 更新已缓存的群成员
+func (cache *Cache) update(group_id qq.GroupID, user_id qq.UserID, update func(member *gocqhttp.GroupMember)) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	g, ok := cache.groups[group_id]
+	if !ok {
+		return
+	}
+
+	member, ok := g.members[user_id]
+	if !ok {
+		return
+	}
+
+	update(&member)
+	g.members[user_id] = member
+}
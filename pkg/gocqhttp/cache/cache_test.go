@@ -0,0 +1,275 @@
+package cache
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"koi/pkg/gocqhttp"
+	"koi/pkg/gocqhttp/qq"
+
+	"github.com/gorilla/websocket"
+)
+
+// 模拟的 go-cqhttp, 记录每个调用的次数
+type fakeServer struct {
+	mu    sync.Mutex
+	calls map[string]int
+	data  map[string]any // 各调用返回的数据
+}
+
+func (server *fakeServer) Calls(action string) int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.calls[action]
+}
+
+func newFakeServer(t *testing.T, data map[string]any) (*fakeServer, *websocket.Conn) {
+	t.Helper()
+
+	server := &fakeServer{calls: make(map[string]int), data: data}
+
+	var upgrader websocket.Upgrader
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		for {
+			var request struct {
+				Action string          `json:"action"`
+				Echo   json.RawMessage `json:"echo"`
+			}
+
+			if ws.ReadJSON(&request) != nil {
+				return
+			}
+
+			server.mu.Lock()
+			server.calls[request.Action]++
+			data := server.data[request.Action]
+			server.mu.Unlock()
+
+			response := map[string]any{"status": "ok", "retcode": 0, "data": data, "echo": request.Echo}
+
+			if ws.WriteJSON(response) != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(httpServer.Close)
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { gocqhttp.Close(ws) })
+
+	return server, ws
+}
+
+// 群通知事件
+func notice(notice_type string, fields map[string]any) []byte {
+	e := map[string]any{"time": 1700000000, "self_id": 1, "post_type": "notice", "notice_type": notice_type}
+	for k, v := range fields {
+		e[k] = v
+	}
+
+	data, _ := json.Marshal(e)
+	return data
+}
+
+func newCache(t *testing.T) (*fakeServer, *Cache) {
+	server, ws := newFakeServer(t, map[string]any{
+		"get_group_list": []map[string]any{{"group_id": 100, "group_name": "a", "member_count": 2}},
+		"get_group_info": map[string]any{"group_id": 100, "group_name": "a", "member_count": 2},
+		"get_group_member_list": []map[string]any{
+			{"group_id": 100, "user_id": 1, "card": "bot", "role": "owner"},
+			{"group_id": 100, "user_id": 2, "card": "two", "role": "member"},
+		},
+		"get_group_member_info": map[string]any{"group_id": 100, "user_id": 3, "card": "three", "role": "member"},
+		"get_friend_list":       []map[string]any{{"user_id": 2, "nickname": "two"}},
+		"get_stranger_info":     map[string]any{"user_id": 4, "nickname": "four"},
+	})
+
+	return server, New(ws, time.Hour)
+}
+
+func member(t *testing.T, cache *Cache, user_id qq.UserID) gocqhttp.GroupMember {
+	t.Helper()
+
+	m, err := cache.Member(100, user_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+func TestCached(t *testing.T) {
+	server, cache := newCache(t)
+
+	for i := 0; i < 2; i++ {
+		if members, err := cache.Members(100); err != nil || len(members) != 2 {
+			t.Fatalf("Members = %v, %v", members, err)
+		}
+
+		if m := member(t, cache, 2); m.Card != "two" {
+			t.Errorf("Card = %q", m.Card)
+		}
+	}
+
+	if got := server.Calls("get_group_member_list"); got != 1 {
+		t.Errorf("get_group_member_list called %d times, want 1", got)
+	}
+
+	if got := server.Calls("get_group_member_info"); got != 0 {
+		t.Errorf("get_group_member_info called %d times, want 0", got)
+	}
+
+	cache.Invalidate()
+
+	if _, err := cache.Members(100); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := server.Calls("get_group_member_list"); got != 2 {
+		t.Errorf("get_group_member_list called %d times after Invalidate, want 2", got)
+	}
+}
+
+func TestHandleMemberUpdates(t *testing.T) {
+	server, cache := newCache(t)
+
+	if _, err := cache.Members(100); err != nil {
+		t.Fatal(err)
+	}
+
+	events := [][]byte{
+		notice("group_card", map[string]any{"group_id": 100, "user_id": 2, "card_new": "new", "card_old": "two"}),
+		notice("group_admin", map[string]any{"sub_type": "set", "group_id": 100, "user_id": 2}),
+		notice("notify", map[string]any{"sub_type": "title", "group_id": 100, "user_id": 2, "title": "king"}),
+	}
+
+	for _, e := range events {
+		if err := cache.Handle(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := member(t, cache, 2)
+	if m.Card != "new" || m.Role != "admin" || m.Title != "king" {
+		t.Errorf("member = %+v, want card new, role admin, title king", m)
+	}
+
+	// 更新来自事件, 不重新获取
+	if got := server.Calls("get_group_member_list") + server.Calls("get_group_member_info"); got != 1 {
+		t.Errorf("member fetched %d times, want 1", got)
+	}
+}
+
+func TestHandleMembership(t *testing.T) {
+	server, cache := newCache(t)
+
+	if _, err := cache.Group(100); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cache.Members(100); err != nil {
+		t.Fatal(err)
+	}
+
+	// 新成员立即获取
+	if err := cache.Handle(notice("group_increase", map[string]any{"sub_type": "approve", "group_id": 100, "user_id": 3, "operator_id": 1})); err != nil {
+		t.Fatal(err)
+	}
+
+	if server.Calls("get_group_member_info") != 1 {
+		t.Error("new member was not fetched")
+	}
+
+	if m := member(t, cache, 3); m.Card != "three" {
+		t.Errorf("new member = %+v", m)
+	}
+
+	if info, _ := cache.Group(100); info.MemberCount != 3 {
+		t.Errorf("MemberCount = %d after increase, want 3", info.MemberCount)
+	}
+
+	// 离开的成员从缓存中移除
+	if err := cache.Handle(notice("group_decrease", map[string]any{"sub_type": "leave", "group_id": 100, "user_id": 2, "operator_id": 2})); err != nil {
+		t.Fatal(err)
+	}
+
+	members, _ := cache.Members(100)
+	for _, m := range members {
+		if m.UserID == 2 {
+			t.Error("member 2 still cached after leaving")
+		}
+	}
+
+	if info, _ := cache.Group(100); info.MemberCount != 2 {
+		t.Errorf("MemberCount = %d after decrease, want 2", info.MemberCount)
+	}
+
+	// 被踢出群后整个群失效, 下次查询重新获取
+	if err := cache.Handle(notice("group_decrease", map[string]any{"sub_type": "kick_me", "group_id": 100, "user_id": 1, "operator_id": 2})); err != nil {
+		t.Fatal(err)
+	}
+
+	before := server.Calls("get_group_info")
+
+	if _, err := cache.Group(100); err != nil {
+		t.Fatal(err)
+	}
+
+	if server.Calls("get_group_info") != before+1 {
+		t.Error("group was not fetched again after kick_me")
+	}
+}
+
+func TestHandleFriendAdd(t *testing.T) {
+	server, cache := newCache(t)
+
+	if _, err := cache.Friends(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Handle(notice("friend_add", map[string]any{"user_id": 4})); err != nil {
+		t.Fatal(err)
+	}
+
+	friend, ok, err := cache.Friend(4)
+	if err != nil || !ok || friend.Nickname != "four" {
+		t.Errorf("Friend(4) = %+v, %v, %v", friend, ok, err)
+	}
+
+	if got := server.Calls("get_friend_list"); got != 1 {
+		t.Errorf("get_friend_list called %d times, want 1", got)
+	}
+}
+
+func TestHandleIgnored(t *testing.T) {
+	server, cache := newCache(t)
+
+	if err := cache.Handle([]byte(`{"time":1,"self_id":1,"post_type":"meta_event","meta_event_type":"heartbeat","interval":5000}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Handle(notice("group_card", map[string]any{"group_id": 100, "user_id": 2, "card_new": "new"})); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未缓存的成员不会因事件被获取
+	if got := server.Calls("get_group_member_list") + server.Calls("get_group_member_info"); got != 0 {
+		t.Errorf("member fetched %d times, want 0", got)
+	}
+}
@@ -0,0 +1,333 @@
+package gocqhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"koi/pkg/gocqhttp/schema"
+
+	"github.com/gorilla/websocket"
+)
+
+// API错误
+type Error struct {
+	Action  string // API终结点
+	Status  string // 响应状态
+	RetCode int    // 返回码
+	Code    string // 错误代码
+	Message string // 错误信息
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("%s: %s", err.Code, err.Message)
+}
+
+// 是否为暂时性错误
+//
+// * go-cqhttp 调用QQ服务器失败时返回 *_API_ERROR, 如网络波动或风控, 而不是参数错误, 可以重试
+//
+// * 发送消息时为 SEND_MSG_API_ERROR, 表示没有收到消息回执, 消息通常因风控未发出
+func (err *Error) Temporary() bool {
+	return err.Code == "API_ERROR" || strings.HasSuffix(err.Code, "_API_ERROR")
+}
+
+// 请求标识计数
+var echo atomic.Uint64
+
+// 关闭连接
+//
+// * 同时清除该连接的设置, 请求状态和能力缓存
+//
+// * 读写失败的连接会被自动关闭
+func Close(ws *websocket.Conn) error {
+	if c, ok := conns.LoadAndDelete(ws); ok {
+		c := c.(*conn)
+
+		c.mu.Lock()
+		live := c.live
+		c.mu.Unlock()
+
+		// 重连后的连接
+		if live != nil && live != ws {
+			live.Close()
+		}
+	}
+
+	ResetCapability(ws)
+
+	return ws.Close()
+}
+
+// 调用结果
+type result struct {
+	data []byte
+	err  error
+}
+
+func (message ws_data) doContext(ctx context.Context, ws *websocket.Conn) ([]byte, error) {
+	var handler Handler = func(ctx context.Context, action string, params any) (json.RawMessage, error) {
+		return ws_data{Action: action, Params: params}.retry(ctx, ws)
+	}
+
+	for i := len(Interceptors) - 1; i >= 0; i-- {
+		interceptor, next := Interceptors[i], handler
+
+		handler = func(ctx context.Context, action string, params any) (json.RawMessage, error) {
+			return interceptor(ctx, action, params, next)
+		}
+	}
+
+	return handler(ctx, message.Action, message.Params)
+}
+
+// 按重试策略调用
+func (message ws_data) retry(ctx context.Context, ws *websocket.Conn) ([]byte, error) {
+	policy := retryPolicy(message.Action)
+
+	for attempt := 1; ; attempt++ {
+		data, err := message.try(ctx, ws)
+		if err == nil || attempt >= policy.Attempts || !policy.retryable(message.Action, err, reconnectable(ws)) {
+			return data, err
+		}
+
+		err = sleep(ctx, policy.backoff(attempt))
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// 调用一次
+func (message ws_data) try(ctx context.Context, ws *websocket.Conn) ([]byte, error) {
+	c := getConn(ws)
+
+	err := c.wait(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := c.socket(ws)
+	if err != nil {
+		c.release()
+		return nil, err
+	}
+
+	message.Action = withVariant(ctx, message.Action, c.config().Variants)
+	message.Echo = "koi-" + strconv.FormatUint(echo.Add(1), 10)
+
+	done := make(chan result, 1)
+	call := new(pending)
+
+	// 只记录收发耗时, 不包括之前的排队和限速
+	sent := time.Now()
+	if t := timingOf(ctx); t != nil {
+		defer func() { t.add(time.Since(sent)) }()
+	}
+
+	// 在独立的协程中收发, ctx 取消后仍会等待响应再释放连接
+	go func() {
+		defer c.release()
+
+		data, err := message.roundTrip(live)
+		c.finish(live, call)
+
+		var api *Error
+		if err != nil && !errors.As(err, &api) {
+			c.fail(ws, err)
+		}
+
+		done <- result{data, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		c.abandon(live, call)
+		return nil, ctx.Err()
+	}
+}
+
+// 获取当前使用的连接
+//
+// * 连接读写失败后通过 Options.Dial 重连
+func (c *conn) socket(ws *websocket.Conn) (*websocket.Conn, error) {
+	c.mu.Lock()
+	live, broken, dial := c.live, c.broken, c.options.Dial
+	c.mu.Unlock()
+
+	if live == nil {
+		live = ws
+	}
+
+	if broken == nil || dial == nil {
+		return live, nil
+	}
+
+	fresh, err := dial()
+	if err != nil {
+		return nil, &notSentError{fmt.Errorf("reconnect: %w", err)}
+	}
+
+	if live != ws {
+		live.Close()
+	}
+
+	c.mu.Lock()
+	c.live = fresh
+	c.broken = nil
+	c.mu.Unlock()
+
+	// 重连后的 go-cqhttp 可能已经重启, 重新探测连接能力
+	ResetCapability(ws)
+
+	return fresh, nil
+}
+
+// 是否可以重连
+//
+// * 不为已关闭的连接创建状态
+func reconnectable(ws *websocket.Conn) bool {
+	c, ok := conns.Load(ws)
+	return ok && c.(*conn).config().Dial != nil
+}
+
+// 连接读写失败
+//
+// * 读写失败后连接不再可用, 设置了 Options.Dial 时在下一次调用前重连, 否则关闭连接并清除状态
+func (c *conn) fail(ws *websocket.Conn, err error) {
+	c.mu.Lock()
+	reconnect := c.options.Dial != nil
+	if reconnect {
+		c.broken = err
+	}
+	c.mu.Unlock()
+
+	if !reconnect {
+		Close(ws)
+	}
+}
+
+// 收发中的调用
+type pending struct {
+	finished  bool // 是否已结束
+	abandoned bool // 是否已被取消
+}
+
+// 放弃等待响应
+//
+// * 设置读取期限, 迟到的响应会因请求标识不匹配而被之后的调用跳过
+func (c *conn) abandon(ws *websocket.Conn, call *pending) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if call.finished {
+		return
+	}
+
+	late := c.options.LateReply
+	if late <= 0 {
+		late = 30 * time.Second
+	}
+
+	call.abandoned = true
+	ws.SetReadDeadline(time.Now().Add(late))
+}
+
+// 结束收发
+func (c *conn) finish(ws *websocket.Conn, call *pending) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call.finished = true
+
+	if call.abandoned {
+		ws.SetReadDeadline(time.Time{})
+	}
+}
+
+// 发送请求并读取对应的响应
+//
+// * 跳过请求标识不匹配的消息
+func (message ws_data) roundTrip(ws *websocket.Conn) ([]byte, error) {
+	err := ws.WriteJSON(message)
+	if err != nil {
+		return nil, &notSentError{err}
+	}
+
+	want := strconv.Quote(message.Echo)
+
+	for {
+		var data *ws_body
+
+		err = ws.ReadJSON(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		// 没有请求标识的是事件或其他客户端的响应
+		if data == nil || string(data.Echo) != want {
+			continue
+		}
+
+		if data.Code != nil || data.Status == "failed" {
+			err := &Error{
+				Action:  message.Action,
+				Status:  data.Status,
+				RetCode: data.HTTPCode,
+			}
+
+			if data.Code != nil {
+				err.Code = *data.Code
+			}
+
+			if data.Message != nil {
+				err.Message = *data.Message
+			}
+
+			return nil, err
+		}
+
+		// 异步和限速调用只返回 status: async, 没有数据
+		if data.Status == "async" {
+			return []byte("null"), nil
+		}
+
+		return data.Body, nil
+	}
+}
+
+// 调用API终结点
+//
+// * 用于 koi 尚未封装的终结点, 与其他API使用相同的连接和错误处理
+//
+// * params 为nil时不发送参数, out 为nil时忽略返回数据
+func Call(ctx context.Context, ws *websocket.Conn, action string, params, out any) error {
+	var message = ws_data{
+		Action: action,
+		Params: params,
+	}
+
+	data, err := message.doContext(ctx, ws)
+	if err != nil {
+		return err
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+
+	return schema.Unmarshal(data, out)
+}
+
+// 调用API终结点并返回指定类型的数据
+func CallAs[T any](ctx context.Context, ws *websocket.Conn, action string, params any) (out T, err error) {
+	err = Call(ctx, ws, action, params, &out)
+	return out, err
+}
@@ -0,0 +1,226 @@
+package gocqhttp
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"koi/pkg/gocqhttp/cqcode"
+	"koi/pkg/log"
+
+	"github.com/gorilla/websocket"
+)
+
+// 连接能力
+type Capability struct {
+	Image  bool  // 是否可以发送图片
+	Record bool  // 是否可以发送语音
+	Poke   bool  // 是否可以发送戳一戳, 手表协议不支持
+	Err    error // 探测失败的原因, 失败时视为全部支持
+}
+
+// 各连接的能力, *websocket.Conn -> *probing
+var capabilities sync.Map
+
+// 探测中或已探测的连接能力
+type probing struct {
+	done       chan struct{} // 探测完成后关闭
+	capability *Capability
+}
+
+// 手表协议
+const protocolWatch = 2
+
+// 获取连接能力
+//
+// * 每个连接只探测一次, 之后返回缓存结果, 正在探测时等待探测完成
+//
+// * 探测失败时同样缓存, 只有本次探测失败才返回错误, 之前的失败原因见 Capability.Err
+func GetCapability(ws *websocket.Conn) (*Capability, error) {
+	entry, loaded := capabilities.LoadOrStore(ws, &probing{done: make(chan struct{})})
+	p := entry.(*probing)
+
+	if loaded {
+		<-p.done
+		return p.capability, nil
+	}
+
+	capability, err := probe(ws)
+	if err != nil {
+		capability = &Capability{Image: true, Record: true, Poke: true, Err: err}
+	}
+
+	p.capability = capability
+	close(p.done)
+
+	return capability, err
+}
+
+// 在后台探测连接能力
+//
+// * 建议在连接建立后调用, 探测完成前发送的消息不会降级
+//
+// * 探测失败时输出警告日志
+func ProbeCapability(ws *websocket.Conn) {
+	go func() {
+		_, err := GetCapability(ws)
+		if err != nil {
+			log.Warn(fmt.Sprintf("probe capability: %s", err))
+		}
+	}()
+}
+
+// 获取已探测的连接能力
+//
+// * 尚未探测时在后台开始探测, 探测完成前返回nil
+func probed(ws *websocket.Conn) *Capability {
+	entry, ok := capabilities.Load(ws)
+	if !ok {
+		ProbeCapability(ws)
+		return nil
+	}
+
+	p := entry.(*probing)
+
+	select {
+	case <-p.done:
+		return p.capability
+	default:
+		return nil
+	}
+}
+
+// 探测连接能力
+func probe(ws *websocket.Conn) (*Capability, error) {
+	image, err := CanSendImage(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := CanSendRecord(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := GetVersionInfo(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Capability{
+		Image:  image,
+		Record: record,
+		Poke:   version.Protocol != protocolWatch,
+	}, nil
+}
+
+// 清除连接能力缓存
+//
+// * Close, 连接读写失败和重连时会自动清除, go-cqhttp 重启后需手动调用
+func ResetCapability(ws *websocket.Conn) {
+	capabilities.Delete(ws)
+}
+
+// 降级方式
+const (
+	FallbackNone = ""     // 不降级
+	FallbackTTS  = "tts"  // 转换为文本转语音, 朗读语音消息段的 text 参数, 没有时转换为文字说明, 仅用于语音
+	FallbackURL  = "url"  // 转换为资源链接, 仅用于图片
+	FallbackText = "text" // 转换为文字说明
+	FallbackDrop = "drop" // 移除
+)
+
+// 降级策略
+type FallbackPolicy struct {
+	Record string // 语音
+	Image  string // 图片
+	Poke   string // 戳一戳
+}
+
+// 不支持的消息段的降级策略
+var Fallback = FallbackPolicy{
+	Record: FallbackText,
+	Image:  FallbackURL,
+	Poke:   FallbackText,
+}
+
+// 降级消息
+//
+// * 根据连接能力和 Fallback 替换不支持的消息段, 没有需要替换的消息段时原样返回
+//
+// * 不会为发送消息等待探测, 探测完成前和探测失败时原样返回, 见 ProbeCapability
+//
+// * 语音消息段可以通过 text 参数附带文字内容, 用于 FallbackTTS, 如 [CQ:record,file=...,text=你好]
+func Degrade(ws *websocket.Conn, text string) string {
+	if Fallback == (FallbackPolicy{}) || !cqcode.Check(text) {
+		return text
+	}
+
+	capability := probed(ws)
+	if capability == nil {
+		return text
+	}
+
+	segments := cqcode.Parse(text)
+
+	// 不支持的消息段的降级方式和文字说明
+	fallback := func(segment cqcode.Segment) (policy, note string) {
+		switch {
+		case segment.Type == "record" && !capability.Record:
+			return Fallback.Record, "[语音]"
+		case segment.Type == "image" && !capability.Image:
+			return Fallback.Image, "[图片]"
+		case segment.Type == "poke" && !capability.Poke:
+			return Fallback.Poke, "[戳一戳]"
+		}
+
+		return FallbackNone, ""
+	}
+
+	degraded := false
+
+	for _, segment := range segments {
+		if policy, _ := fallback(segment); policy != FallbackNone {
+			degraded = true
+			break
+		}
+	}
+
+	if !degraded {
+		return text
+	}
+
+	var message cqcode.Message
+
+	for _, segment := range segments {
+		policy, note := fallback(segment)
+		message = append(message, degrade(segment, policy, note)...)
+	}
+
+	return message.String()
+}
+
+func degrade(segment cqcode.Segment, policy, note string) cqcode.Message {
+	text := func(text string) cqcode.Message {
+		return cqcode.Message{{Type: "text", Data: map[string]string{"text": text}}}
+	}
+
+	switch policy {
+	case FallbackNone:
+		return cqcode.Message{segment}
+	case FallbackDrop:
+		return nil
+	case FallbackTTS:
+		if segment.Type == "record" && segment.Data["text"] != "" {
+			return cqcode.Message{{Type: "tts", Data: map[string]string{"text": segment.Data["text"]}}}
+		}
+	case FallbackURL:
+		for _, url := range []string{segment.Data["url"], segment.Data["file"]} {
+			if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+				return text(url)
+			}
+		}
+	}
+
+	return text(note)
+}
@@ -0,0 +1,109 @@
+package gocqhttp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// 模拟手表协议且不能发送语音的 go-cqhttp
+func newWatchServer(t *testing.T) (*fakeServer, func(text string) string) {
+	server, ws := newFakeServer(t, func(action string, _ json.RawMessage) map[string]any {
+		switch action {
+		case "can_send_image":
+			return ok(map[string]any{"yes": true})
+		case "can_send_record":
+			return ok(map[string]any{"yes": false})
+		case "get_version_info":
+			return ok(map[string]any{"protocol_name": protocolWatch})
+		}
+
+		return ok(nil)
+	})
+
+	if _, err := GetCapability(ws); err != nil {
+		t.Fatal(err)
+	}
+
+	return server, func(text string) string { return Degrade(ws, text) }
+}
+
+func TestDegrade(t *testing.T) {
+	saved := Fallback
+	t.Cleanup(func() { Fallback = saved })
+
+	Fallback = FallbackPolicy{Record: FallbackTTS, Image: FallbackURL, Poke: FallbackText}
+
+	_, degrade := newWatchServer(t)
+
+	tests := []struct {
+		text, want string
+	}{
+		// 没有需要降级的消息段时原样返回
+		{"[CQ:face,id=1] &#91;x&#93; [CQ:at,qq=2,name=a]", "[CQ:face,id=1] &#91;x&#93; [CQ:at,qq=2,name=a]"},
+		{"[CQ:image,file=a.png]", "[CQ:image,file=a.png]"},
+		{"hi[CQ:poke,qq=1]", "hi&#91;戳一戳&#93;"},
+		{"[CQ:record,file=a.amr,text=你好]", "[CQ:tts,text=你好]"},
+		{"[CQ:record,file=a.amr]", "&#91;语音&#93;"},
+	}
+
+	for _, test := range tests {
+		if got := degrade(test.text); got != test.want {
+			t.Errorf("Degrade(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestDegradeDoesNotProbeInline(t *testing.T) {
+	hold := make(chan struct{})
+
+	server, ws := newFakeServer(t, func(action string, _ json.RawMessage) map[string]any {
+		if action == "can_send_image" {
+			<-hold
+		}
+
+		return ok(map[string]any{"yes": false})
+	})
+
+	// 探测被阻塞时发送不会等待, 消息原样返回
+	done := make(chan string)
+	go func() { done <- Degrade(ws, "[CQ:image,file=a.png]") }()
+
+	select {
+	case got := <-done:
+		if got != "[CQ:image,file=a.png]" {
+			t.Errorf("Degrade before probe = %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Degrade waited for the probe")
+	}
+
+	close(hold)
+
+	if _, err := GetCapability(ws); err != nil {
+		t.Fatal(err)
+	}
+
+	// 只探测一次
+	count := 0
+	for _, action := range server.Actions() {
+		if action == "can_send_image" {
+			count++
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("probed %d times, want 1", count)
+	}
+}
+
+func TestCapabilityClearedOnClose(t *testing.T) {
+	_, ws := newFakeServer(t, nil)
+
+	GetCapability(ws)
+	Close(ws)
+
+	if _, ok := capabilities.Load(ws); ok {
+		t.Error("capability was not cleared on Close")
+	}
+}
@@ -0,0 +1,213 @@
+package cqcode
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+)
+
+// 新闻/链接卡片
+type NewsCard struct {
+	Title      string `json:"title"`                 // 标题
+	Desc       string `json:"desc"`                  // 摘要
+	Preview    string `json:"preview"`               // 预览图URL
+	Tag        string `json:"tag"`                   // 来源名称
+	JumpURL    string `json:"jumpUrl"`               // 跳转链接
+	SourceIcon string `json:"source_icon,omitempty"` // 来源图标URL
+}
+
+// 音乐卡片
+type MusicCard struct {
+	Title    string `json:"title"`    // 标题
+	Desc     string `json:"desc"`     // 歌手或简介
+	Preview  string `json:"preview"`  // 封面URL
+	Tag      string `json:"tag"`      // 来源名称
+	JumpURL  string `json:"jumpUrl"`  // 跳转链接
+	MusicURL string `json:"musicUrl"` // 音频URL
+}
+
+// 大图卡片
+type ImageCard struct {
+	Brief   string // 消息列表中显示的摘要
+	URL     string // 跳转链接
+	Cover   string // 图片URL
+	Title   string // 标题
+	Summary string // 描述
+	Source  string // 来源名称
+}
+
+// ark卡片
+type ark struct {
+	App    string                     `json:"app"`
+	Desc   string                     `json:"desc"`
+	View   string                     `json:"view"`
+	Ver    string                     `json:"ver"`
+	Prompt string                     `json:"prompt"`
+	Meta   map[string]json.RawMessage `json:"meta"`
+}
+
+func marshalArk(view, desc, title string, meta any) (string, error) {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+
+	card := ark{
+		App:    "com.tencent.structmsg",
+		Desc:   desc,
+		View:   view,
+		Ver:    "0.0.0.1",
+		Prompt: "[分享]" + title,
+		Meta:   map[string]json.RawMessage{view: data},
+	}
+
+	data, err = json.Marshal(card)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// 卡片JSON数据
+func (card NewsCard) Payload() (string, error) {
+	return marshalArk("news", "新闻", card.Title, card)
+}
+
+// 卡片CQ码
+func (card NewsCard) Code() (string, error) {
+	payload, err := card.Payload()
+	if err != nil {
+		return "", err
+	}
+
+	return JSON(payload), nil
+}
+
+// 卡片JSON数据
+func (card MusicCard) Payload() (string, error) {
+	return marshalArk("music", "音乐", card.Title, card)
+}
+
+// 卡片CQ码
+func (card MusicCard) Code() (string, error) {
+	payload, err := card.Payload()
+	if err != nil {
+		return "", err
+	}
+
+	return JSON(payload), nil
+}
+
+// 大图卡片XML
+type image_card struct {
+	XMLName    xml.Name `xml:"msg"`
+	ServiceID  int      `xml:"serviceID,attr"`
+	TemplateID int      `xml:"templateID,attr"`
+	Action     string   `xml:"action,attr"`
+	Brief      string   `xml:"brief,attr"`
+	URL        string   `xml:"url,attr"`
+	Item       struct {
+		Layout  int `xml:"layout,attr"`
+		Picture struct {
+			Cover string `xml:"cover,attr"`
+		} `xml:"picture"`
+		Title   string `xml:"title"`
+		Summary string `xml:"summary"`
+	} `xml:"item"`
+	Source struct {
+		Name string `xml:"name,attr"`
+	} `xml:"source"`
+}
+
+// 卡片XML数据
+func (card ImageCard) Payload() (string, error) {
+	var msg image_card
+
+	msg.ServiceID = 1
+	msg.TemplateID = 1
+	msg.Action = "web"
+	msg.Brief = card.Brief
+	msg.URL = card.URL
+	msg.Item.Picture.Cover = card.Cover
+	msg.Item.Title = card.Title
+	msg.Item.Summary = card.Summary
+	msg.Source.Name = card.Source
+
+	data, err := xml.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return `<?xml version="1.0" encoding="utf-8"?>` + string(data), nil
+}
+
+// 卡片CQ码
+func (card ImageCard) Code() (string, error) {
+	payload, err := card.Payload()
+	if err != nil {
+		return "", err
+	}
+
+	return XML(payload), nil
+}
+
+// 解析卡片
+//
+// * 支持 json 和 xml 消息段的数据, 返回 *NewsCard, *MusicCard 或 *ImageCard
+//
+// * 无法识别时 ok 为false
+func ParseCard(data string) (card any, ok bool) {
+	data = strings.TrimSpace(data)
+
+	if strings.HasPrefix(data, "<") {
+		var msg image_card
+
+		if xml.Unmarshal([]byte(data), &msg) != nil || msg.Item.Picture.Cover == "" {
+			return nil, false
+		}
+
+		return &ImageCard{
+			Brief:   msg.Brief,
+			URL:     msg.URL,
+			Cover:   msg.Item.Picture.Cover,
+			Title:   msg.Item.Title,
+			Summary: msg.Item.Summary,
+			Source:  msg.Source.Name,
+		}, true
+	}
+
+	var msg ark
+
+	if json.Unmarshal([]byte(data), &msg) != nil {
+		return nil, false
+	}
+
+	if meta, exists := msg.Meta["news"]; exists {
+		var news NewsCard
+		if json.Unmarshal(meta, &news) == nil {
+			return &news, true
+		}
+	}
+
+	if meta, exists := msg.Meta["music"]; exists {
+		var music MusicCard
+		if json.Unmarshal(meta, &music) == nil {
+			return &music, true
+		}
+	}
+
+	return nil, false
+}
+
+// 解析卡片消息段
+//
+// * 仅处理 json 和 xml 消息段
+func (segment Segment) Card() (card any, ok bool) {
+	switch segment.Type {
+	case "json", "xml":
+		return ParseCard(segment.Data["data"])
+	}
+
+	return nil, false
+}
@@ -3,50 +3,103 @@ package cqcode
 import (
 	"bytes"
 	"fmt"
+	"net/url"
 	"regexp"
+	"sort"
 	"strings"
+
+	"koi/pkg/gocqhttp/qq"
 )
 
 // 获取文件URL
+//
+// * file: go-cqhttp 所在主机上的绝对路径, 如 /data/a.png 或 C:\data\a.png
 func GetFileURL(file string) string {
-	return fmt.Sprint("file:///", file)
+	file = strings.ReplaceAll(file, "\\", "/")
+
+	if !strings.HasPrefix(file, "/") {
+		file = "/" + file
+	}
+
+	uri := url.URL{Scheme: "file", Path: file}
+
+	return uri.String()
 }
 
+// Base64图片
+//
+// * code: 已经过Base64编码的图片数据
 func Base64Image(code string) string {
 	return "base64://" + code
 }
 
+// CQ码
+var regex = regexp.MustCompile(`(?i)\[CQ:[^\]]+\]`)
+
 // 查找CQ码
 func Find(text string) []string {
-	regex := regexp.MustCompile(`(?i)\[CQ:(.|\n)+\]`)
 	return regex.FindAllString(text, -1)
 }
 
 // 替换CQ码
 func Replace(src, repl string) string {
-	regex := regexp.MustCompile(`(?i)\[CQ:(.|\n)+\]`)
 	return regex.ReplaceAllString(src, repl)
 }
 
 // 检查
 func Check(code string) bool {
-	regex := regexp.MustCompile(`(?i)\[CQ:(.|\n)+\]`)
 	return regex.MatchString(code)
 }
 
+// 转义纯文本
+//
+// * 转义后的文本不会被解析为CQ码, 可安全拼接不可信的用户输入
+func Escape(text string) string {
+	text = strings.ReplaceAll(text, "&", "&amp;")
+	text = strings.ReplaceAll(text, "[", "&#91;")
+	text = strings.ReplaceAll(text, "]", "&#93;")
+
+	return text
+}
+
+// 转义CQ码参数
+func EscapeParam(value string) string {
+	return strings.ReplaceAll(Escape(value), ",", "&#44;")
+}
+
+// 反转义
+func Unescape(text string) string {
+	text = strings.ReplaceAll(text, "&#44;", ",")
+	text = strings.ReplaceAll(text, "&#91;", "[")
+	text = strings.ReplaceAll(text, "&#93;", "]")
+	text = strings.ReplaceAll(text, "&amp;", "&")
+
+	return text
+}
+
 // 编码CQcode
+//
+// * 参数按键名排序, 相同的参数总是得到相同的CQ码
+//
+// * 参数值会被 EscapeParam 转义, 应传入原始值, 已转义的值会被再次转义, 如 "&#44;" 编码为 "&amp;#44;"
 func Encode(function string, data map[string]interface{}) string {
 	var buffer bytes.Buffer
 
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	buffer.WriteString("[")
 	buffer.WriteString("CQ:")
 	buffer.WriteString(function)
 
-	for k, v := range data {
+	for _, k := range keys {
 		buffer.WriteString(",")
 		buffer.WriteString(k)
 		buffer.WriteString("=")
-		buffer.WriteString(fmt.Sprint(v))
+		buffer.WriteString(EscapeParam(fmt.Sprint(data[k])))
 	}
 
 	buffer.WriteString("]")
@@ -58,43 +111,33 @@ func Encode(function string, data map[string]interface{}) string {
 func Decode(code string) (function string, data map[string]string) {
 	data = make(map[string]string)
 
-	regex := regexp.MustCompile(`(?i)\[CQ:(.|\n)+\]`)
 	code = regex.FindString(code)
 
 	code = strings.TrimPrefix(code, "[")
 	code = strings.TrimSuffix(code, "]")
 
 	slices := strings.Split(code, ",")
-	regex = regexp.MustCompile(`(?i)CQ:`)
-	for _, slice := range slices {
-		if regex.MatchString(slice) {
+	for i, slice := range slices {
+		if i == 0 {
 			index := strings.Index(slice, ":")
 
 			function = slice[index+1:]
+			continue
 		}
 
 		if strings.Contains(slice, "=") {
 			index := strings.Index(slice, "=")
 
-			data[slice[:index]] = slice[index+1:]
+			data[slice[:index]] = Unescape(slice[index+1:])
 		}
 	}
 
-	if strings.EqualFold(function, "json") {
-		json := data["data"]
-
-		json = strings.ReplaceAll(json, "&#44;", ",")
-		json = strings.ReplaceAll(json, "&amp;", "&")
-		json = strings.ReplaceAll(json, "&#91;", "[")
-		json = strings.ReplaceAll(json, "&#93;", "]")
-
-		data["data"] = json
-	}
-
 	return
 }
 
 // 表情
+//
+// * 表情ID可通过 FaceByName 查询
 func Face(id int) string {
 	data := map[string]interface{}{
 		"id": id,
@@ -142,7 +185,7 @@ func Video(file, cover string) string {
 // @某人
 //
 // * 可选参数: name
-func At(uid int64, name string) string {
+func At(uid qq.UserID, name string) string {
 	data := map[string]interface{}{
 		"qq": uid,
 	}
@@ -268,7 +311,7 @@ func ShowImage(file, url string, id int) string {
 }
 
 // 回复
-func Reply(message_id int) string {
+func Reply(message_id qq.MessageID) string {
 	data := map[string]interface{}{
 		"id": message_id,
 	}
@@ -277,7 +320,7 @@ func Reply(message_id int) string {
 }
 
 // 自定义回复
-func ReplyCustom(text string, uid, seq, time int64) string {
+func ReplyCustom(text string, uid qq.UserID, seq, time int64) string {
 	data := map[string]interface{}{
 		"text": text,
 		"qq":   uid,
@@ -289,7 +332,7 @@ func ReplyCustom(text string, uid, seq, time int64) string {
 }
 
 // 戳一戳
-func Poke(uid int64) string {
+func Poke(uid qq.UserID) string {
 	data := map[string]interface{}{
 		"qq": uid,
 	}
@@ -308,11 +351,6 @@ func XML(code string) string {
 
 // JSON消息
 func JSON(json string) string {
-	json = strings.ReplaceAll(json, ",", "&#44;")
-	json = strings.ReplaceAll(json, "&", "&amp;")
-	json = strings.ReplaceAll(json, "[", "&#91;")
-	json = strings.ReplaceAll(json, "]", "&#93;`")
-
 	data := map[string]interface{}{
 		"data": json,
 	}
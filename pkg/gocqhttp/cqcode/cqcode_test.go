@@ -0,0 +1,115 @@
+package cqcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []string{
+		"",
+		"hello",
+		"[CQ:at,qq=all]",
+		"a&b",
+		"&#91;已转义&#93;",
+		"&amp;#44;",
+		"a,b",
+	}
+
+	for _, text := range tests {
+		if got := Unescape(Escape(text)); got != text {
+			t.Errorf("Unescape(Escape(%q)) = %q", text, got)
+		}
+
+		if got := Unescape(EscapeParam(text)); got != text {
+			t.Errorf("Unescape(EscapeParam(%q)) = %q", text, got)
+		}
+
+		if Check(Escape(text)) {
+			t.Errorf("Escape(%q) = %q still contains a CQ code", text, Escape(text))
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	code := Encode("share", map[string]interface{}{
+		"url":   "https://a/?x=1,y=[2]",
+		"title": "a&b",
+	})
+
+	if want := "[CQ:share,title=a&amp;b,url=https://a/?x=1&#44;y=&#91;2&#93;]"; code != want {
+		t.Errorf("Encode = %q, want %q", code, want)
+	}
+
+	function, data := Decode(code)
+	if function != "share" || data["url"] != "https://a/?x=1,y=[2]" || data["title"] != "a&b" {
+		t.Errorf("Decode(%q) = %q, %v", code, function, data)
+	}
+
+	// 已转义的值会被再次转义
+	if got, want := Encode("at", map[string]interface{}{"name": "a&#44;b"}), "[CQ:at,name=a&amp;#44;b]"; got != want {
+		t.Errorf("Encode of pre-escaped value = %q, want %q", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	text := "[CQ:reply,id=1]你好&#91;x&#93;[CQ:at,qq=2] a&amp;b[CQ:face,id=14]"
+
+	want := Message{
+		{"reply", map[string]string{"id": "1"}},
+		{"text", map[string]string{"text": "你好[x]"}},
+		{"at", map[string]string{"qq": "2"}},
+		{"text", map[string]string{"text": " a&b"}},
+		{"face", map[string]string{"id": "14"}},
+	}
+
+	message := Parse(text)
+
+	if !reflect.DeepEqual(message, want) {
+		t.Errorf("Parse(%q) = %v, want %v", text, message, want)
+	}
+
+	if got := message.String(); got != text {
+		t.Errorf("Parse(%q).String() = %q", text, got)
+	}
+}
+
+func TestParseStringRoundTrip(t *testing.T) {
+	message := Message{
+		{"text", map[string]string{"text": "[CQ:at,qq=all] & ,"}},
+		{"image", map[string]string{"file": "a,b].png", "url": "https://a/?b=1&c=[2]"}},
+		{"text", map[string]string{"text": "&#91;"}},
+	}
+
+	if got := Parse(message.String()); !reflect.DeepEqual(got, message) {
+		t.Errorf("Parse(String()) = %v, want %v", got, message)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		text  string
+		allow []string
+		want  string
+	}{
+		{"hi[CQ:at,qq=all][CQ:face,id=14]", []string{"face"}, "hi[CQ:face,id=14]"},
+		{"[CQ:image,file=a.png]", nil, ""},
+		{"[cq:AT,qq=1]", []string{"at"}, "[CQ:AT,qq=1]"},
+
+		// 转义的文本保持转义, 不会变成CQ码
+		{"&#91;CQ:at,qq=all&#93;", nil, "&#91;CQ:at,qq=all&#93;"},
+		{"a&amp;b&#44;", nil, "a&amp;b,"},
+	}
+
+	for _, test := range tests {
+		got := Sanitize(test.text, test.allow...)
+
+		if got != test.want {
+			t.Errorf("Sanitize(%q, %v) = %q, want %q", test.text, test.allow, got, test.want)
+		}
+
+		if len(test.allow) == 0 && Check(got) {
+			t.Errorf("Sanitize(%q) = %q still contains a CQ code", test.text, got)
+		}
+	}
+}
@@ -0,0 +1,439 @@
+package cqcode
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 表情信息
+type FaceInfo struct {
+	ID      int    // 表情ID
+	Name    string // 中文名称
+	English string // 英文名称
+}
+
+// 短代码, 如 /微笑
+func (face FaceInfo) Shortcode() string {
+	return "/" + face.Name
+}
+
+// 表情列表
+var Faces = []FaceInfo{
+	{0, "惊讶", "Surprised"},
+	{1, "撇嘴", "Pouting"},
+	{2, "色", "Drooling"},
+	{3, "发呆", "Dazed"},
+	{4, "得意", "Proud"},
+	{5, "流泪", "Tears"},
+	{6, "害羞", "Shy"},
+	{7, "闭嘴", "Silent"},
+	{8, "睡", "Sleep"},
+	{9, "大哭", "Crying"},
+	{10, "尴尬", "Awkward"},
+	{11, "发怒", "Angry"},
+	{12, "调皮", "Tongue"},
+	{13, "呲牙", "Grin"},
+	{14, "微笑", "Smile"},
+	{15, "难过", "Frown"},
+	{16, "酷", "Cool"},
+	{18, "抓狂", "Frantic"},
+	{19, "吐", "Puke"},
+	{20, "偷笑", "Chuckle"},
+	{21, "可爱", "Lovely"},
+	{22, "白眼", "Eye Roll"},
+	{23, "傲慢", "Arrogant"},
+	{24, "饥饿", "Hungry"},
+	{25, "困", "Drowsy"},
+	{26, "惊恐", "Terrified"},
+	{27, "流汗", "Sweat"},
+	{28, "憨笑", "Laugh"},
+	{29, "悠闲", "Leisurely"},
+	{30, "奋斗", "Determined"},
+	{31, "咒骂", "Curse"},
+	{32, "疑问", "Question"},
+	{33, "嘘", "Shush"},
+	{34, "晕", "Dizzy"},
+	{35, "折磨", "Tormented"},
+	{36, "衰", "Toasted"},
+	{37, "骷髅", "Skull"},
+	{38, "敲打", "Hammer"},
+	{39, "再见", "Bye"},
+	{41, "发抖", "Shiver"},
+	{42, "爱情", "Love"},
+	{43, "跳跳", "Jump"},
+	{46, "猪头", "Pig"},
+	{49, "拥抱", "Hug"},
+	{53, "蛋糕", "Cake"},
+	{54, "闪电", "Lightning"},
+	{55, "炸弹", "Bomb"},
+	{56, "刀", "Knife"},
+	{57, "足球", "Soccer"},
+	{59, "便便", "Poop"},
+	{60, "咖啡", "Coffee"},
+	{61, "饭", "Rice"},
+	{63, "玫瑰", "Rose"},
+	{64, "凋谢", "Wilt"},
+	{66, "爱心", "Heart"},
+	{67, "心碎", "Broken Heart"},
+	{69, "礼物", "Gift"},
+	{74, "太阳", "Sun"},
+	{75, "月亮", "Moon"},
+	{76, "赞", "Thumbs Up"},
+	{77, "踩", "Thumbs Down"},
+	{78, "握手", "Shake"},
+	{79, "胜利", "Victory"},
+	{85, "飞吻", "Blow Kiss"},
+	{86, "怄火", "Sulk"},
+	{89, "西瓜", "Watermelon"},
+	{96, "冷汗", "Cold Sweat"},
+	{97, "擦汗", "Wipe Sweat"},
+	{98, "抠鼻", "Pick Nose"},
+	{99, "鼓掌", "Clap"},
+	{100, "糗大了", "Embarrassed"},
+	{101, "坏笑", "Sneer"},
+	{102, "左哼哼", "Left Humph"},
+	{103, "右哼哼", "Right Humph"},
+	{104, "哈欠", "Yawn"},
+	{105, "鄙视", "Contempt"},
+	{106, "委屈", "Wronged"},
+	{107, "快哭了", "About To Cry"},
+	{108, "阴险", "Sly"},
+	{109, "左亲亲", "Kiss Left"},
+	{110, "吓", "Scared"},
+	{111, "可怜", "Pitiful"},
+	{112, "菜刀", "Cleaver"},
+	{113, "啤酒", "Beer"},
+	{114, "篮球", "Basketball"},
+	{115, "乒乓", "Ping Pong"},
+	{116, "示爱", "Show Love"},
+	{117, "瓢虫", "Ladybug"},
+	{118, "抱拳", "Salute"},
+	{119, "勾引", "Beckon"},
+	{120, "拳头", "Fist"},
+	{121, "差劲", "Pinky"},
+	{122, "爱你", "Love You"},
+	{123, "NO", "No"},
+	{124, "OK", "OK"},
+	{125, "转圈", "Spin"},
+	{126, "磕头", "Kowtow"},
+	{127, "回头", "Turn Back"},
+	{128, "跳绳", "Jump Rope"},
+	{129, "挥手", "Wave"},
+	{130, "激动", "Excited"},
+	{131, "街舞", "Street Dance"},
+	{132, "献吻", "Kiss"},
+	{133, "左太极", "Left Taichi"},
+	{134, "右太极", "Right Taichi"},
+	{136, "双喜", "Double Happiness"},
+	{137, "鞭炮", "Firecracker"},
+	{138, "灯笼", "Lantern"},
+	{140, "K歌", "Karaoke"},
+	{144, "喝彩", "Cheer"},
+	{145, "祈祷", "Pray"},
+	{146, "爆筋", "Vein"},
+	{147, "棒棒糖", "Lollipop"},
+	{148, "喝奶", "Milk"},
+	{151, "飞机", "Plane"},
+	{158, "钞票", "Money"},
+	{168, "药", "Pill"},
+	{169, "手枪", "Pistol"},
+	{171, "茶", "Tea"},
+	{172, "眨眼睛", "Wink"},
+	{173, "泪奔", "Crying Run"},
+	{174, "无奈", "Helpless"},
+	{175, "卖萌", "Act Cute"},
+	{176, "小纠结", "Tangled"},
+	{177, "喷血", "Spit Blood"},
+	{178, "斜眼笑", "Side Glance"},
+	{179, "doge", "Doge"},
+	{180, "惊喜", "Surprise"},
+	{181, "骚扰", "Tease"},
+	{182, "笑哭", "Tears Of Joy"},
+	{183, "我最美", "Beautiful Me"},
+	{184, "河蟹", "Crab"},
+	{185, "羊驼", "Alpaca"},
+	{187, "幽灵", "Ghost"},
+	{188, "蛋", "Egg"},
+	{190, "菊花", "Chrysanthemum"},
+	{192, "红包", "Red Packet"},
+	{193, "大笑", "Big Laugh"},
+	{194, "不开心", "Unhappy"},
+	{197, "冷漠", "Indifferent"},
+	{198, "呃", "Uh"},
+	{199, "好棒", "Great"},
+	{200, "拜托", "Please"},
+	{201, "点赞", "Like"},
+	{202, "无聊", "Bored"},
+	{203, "托脸", "Face Palm"},
+	{204, "吃", "Eat"},
+	{205, "送花", "Give Flower"},
+	{206, "害怕", "Afraid"},
+	{207, "花痴", "Infatuated"},
+	{208, "小样儿", "Smug"},
+	{210, "飙泪", "Gush Tears"},
+	{211, "我不看", "Not Looking"},
+	{212, "托腮", "Chin Rest"},
+	{214, "啵啵", "Smooch"},
+	{215, "糊脸", "Smear Face"},
+	{216, "拍头", "Pat Head"},
+	{217, "扯一扯", "Pull"},
+	{218, "舔一舔", "Lick"},
+	{219, "蹭一蹭", "Nuzzle"},
+	{220, "拽炸天", "Swagger"},
+	{221, "顶呱呱", "Top Notch"},
+	{222, "抱抱", "Hugs"},
+	{223, "暴击", "Critical Hit"},
+	{224, "开枪", "Shoot"},
+	{225, "撩一撩", "Flirt"},
+	{226, "拍桌", "Slam Table"},
+	{227, "拍手", "Applause"},
+	{228, "恭喜", "Congrats"},
+	{229, "干杯", "Cheers"},
+	{230, "嘲讽", "Mock"},
+	{231, "哼", "Hmph"},
+	{232, "佛系", "Zen"},
+	{233, "掐一掐", "Pinch"},
+	{234, "惊呆", "Stunned"},
+	{235, "颤抖", "Tremble"},
+	{236, "啃头", "Bite Head"},
+	{237, "偷看", "Peek"},
+	{238, "扇脸", "Slap"},
+	{239, "原谅", "Forgive"},
+	{240, "喷脸", "Spray Face"},
+	{241, "生日快乐", "Happy Birthday"},
+	{242, "头撞击", "Headbutt"},
+	{243, "甩头", "Head Flip"},
+	{244, "扔狗", "Throw Dog"},
+	{245, "加油必胜", "Fight To Win"},
+	{246, "加油抱抱", "Cheer Hug"},
+	{247, "口罩护体", "Mask"},
+	{260, "搬砖中", "Grinding"},
+	{261, "忙到飞起", "Super Busy"},
+	{262, "脑阔疼", "Headache"},
+	{263, "沧桑", "Weathered"},
+	{264, "捂脸", "Facepalm"},
+	{265, "辣眼睛", "Eye Sore"},
+	{266, "哦哟", "Oh Yo"},
+	{267, "头秃", "Bald"},
+	{268, "问号脸", "Question Face"},
+	{269, "暗中观察", "Lurking"},
+	{270, "emm", "Emm"},
+	{271, "吃瓜", "Eating Melon"},
+	{272, "呵呵哒", "Hehe"},
+	{273, "我酸了", "Jealous"},
+	{274, "太南了", "So Hard"},
+	{276, "辣椒酱", "Chili Sauce"},
+	{277, "汪汪", "Woof"},
+	{278, "汗", "Sweatdrop"},
+	{279, "打脸", "Face Slap"},
+	{280, "击掌", "High Five"},
+	{281, "无眼笑", "Eyeless Laugh"},
+	{282, "敬礼", "Salute Hand"},
+	{283, "狂笑", "Guffaw"},
+	{284, "面无表情", "Expressionless"},
+	{285, "摸鱼", "Slacking"},
+	{286, "魔鬼笑", "Devil Smile"},
+	{287, "哦", "Oh"},
+	{288, "请", "Please Go Ahead"},
+	{289, "睁眼", "Eyes Open"},
+	{290, "敲开心", "So Happy"},
+	{291, "震惊", "Shocked"},
+	{292, "让我康康", "Let Me See"},
+	{293, "摸锦鲤", "Lucky Koi"},
+	{294, "期待", "Expecting"},
+	{295, "拿到红包", "Got Red Packet"},
+	{296, "真好", "So Nice"},
+	{297, "拜谢", "Thank You"},
+	{298, "元宝", "Ingot"},
+	{299, "牛啊", "Awesome"},
+	{300, "胖三斤", "Gain Weight"},
+	{301, "好闪", "So Shiny"},
+	{302, "左拜年", "New Year Left"},
+	{303, "右拜年", "New Year Right"},
+	{304, "红包包", "Red Packets"},
+	{305, "右亲亲", "Kiss Right"},
+	{306, "牛气冲天", "Bullish"},
+	{307, "喵喵", "Meow"},
+	{308, "求红包", "Beg Red Packet"},
+	{309, "谢红包", "Thanks Red Packet"},
+	{310, "新年烟花", "New Year Fireworks"},
+	{311, "打call", "Cheer On"},
+	{312, "变形", "Transform"},
+	{313, "嗑到了", "Shipping"},
+	{314, "仔细分析", "Analyze"},
+	{315, "加油", "Go For It"},
+	{316, "我没事", "I Am Fine"},
+	{317, "菜汪", "Noob Dog"},
+	{318, "崇拜", "Worship"},
+	{319, "比心", "Finger Heart"},
+	{320, "庆祝", "Celebrate"},
+	{321, "老色痞", "Old Pervert"},
+	{322, "拒绝", "Refuse"},
+	{323, "嫌弃", "Disdain"},
+	{324, "吃糖", "Eat Candy"}}
+
+var (
+	faceByID   = make(map[int]FaceInfo)
+	faceByName = make(map[string]FaceInfo)
+	faceNames  []string // 按长度降序排列, 用于最长匹配
+)
+
+func init() {
+	for _, face := range Faces {
+		faceByID[face.ID] = face
+		faceByName[face.Name] = face
+		faceByName[strings.ToLower(face.English)] = face
+
+		faceNames = append(faceNames, face.Name)
+	}
+
+	sort.Slice(faceNames, func(i, j int) bool {
+		return len(faceNames[i]) > len(faceNames[j])
+	})
+}
+
+// 根据ID查找表情
+func FaceByID(id int) (face FaceInfo, ok bool) {
+	face, ok = faceByID[id]
+	return
+}
+
+// 根据中文或英文名称查找表情
+//
+// * 英文名称不区分大小写
+func FaceByName(name string) (face FaceInfo, ok bool) {
+	face, ok = faceByName[name]
+	if !ok {
+		face, ok = faceByName[strings.ToLower(name)]
+	}
+
+	return
+}
+
+// 表情名称
+//
+// * 未知表情返回"表情"
+func FaceName(id int) string {
+	face, ok := FaceByID(id)
+	if !ok {
+		return "表情"
+	}
+
+	return face.Name
+}
+
+// 将文本中的短代码转换为表情
+//
+// * 如 "早上好/微笑" 转换为 "早上好[CQ:face,id=14]", 已有的CQ码保持不变
+func ShortcodeToFaces(text string) string {
+	var message Message
+
+	for _, segment := range Parse(text) {
+		if segment.Type != "text" {
+			message = append(message, segment)
+			continue
+		}
+
+		message = append(message, expandFaces(segment.Data["text"])...)
+	}
+
+	return message.String()
+}
+
+func expandFaces(text string) (message Message) {
+	var builder strings.Builder
+
+	var prev byte // 上一个字节, 用于判断短代码的边界
+
+	for len(text) > 0 {
+		if text[0] == '/' {
+			if name := matchFace(prev, text[1:]); name != "" {
+				if builder.Len() > 0 {
+					message = append(message, Segment{"text", map[string]string{"text": builder.String()}})
+					builder.Reset()
+				}
+
+				face := faceByName[name]
+				message = append(message, Segment{"face", map[string]string{"id": strconv.Itoa(face.ID)}})
+
+				prev = name[len(name)-1]
+				text = text[1+len(name):]
+				continue
+			}
+		}
+
+		prev = text[0]
+		builder.WriteByte(text[0])
+		text = text[1:]
+	}
+
+	if builder.Len() > 0 {
+		message = append(message, Segment{"text", map[string]string{"text": builder.String()}})
+	}
+
+	return message
+}
+
+// 最长匹配表情名称
+//
+// * 英文名称如 /OK 需要前后都是单词边界, 且不能位于链接或路径中, 避免匹配 /NOTE 和 http://a/OK
+func matchFace(prev byte, text string) string {
+	for _, name := range faceNames {
+		if !strings.HasPrefix(text, name) {
+			continue
+		}
+
+		if !ascii(name) {
+			return name
+		}
+
+		if pathByte(prev) || (len(text) > len(name) && wordByte(text[len(name)])) {
+			continue
+		}
+
+		return name
+	}
+
+	return ""
+}
+
+func ascii(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= 0x80 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// 是否为单词中的字符
+func wordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// 是否为单词, 链接或路径中的字符
+func pathByte(b byte) bool {
+	return wordByte(b) || strings.IndexByte("/:.-~%?=&#", b) >= 0
+}
+
+// 将消息中的表情转换为短代码
+//
+// * 如 "早上好[CQ:face,id=14]" 转换为 "早上好/微笑", 未知表情保持不变
+func FacesToShortcode(text string) string {
+	var message Message
+
+	for _, segment := range Parse(text) {
+		if segment.Type == "face" {
+			id, err := strconv.Atoi(segment.Data["id"])
+			if face, ok := FaceByID(id); err == nil && ok {
+				segment = Segment{"text", map[string]string{"text": face.Shortcode()}}
+			}
+		}
+
+		message = append(message, segment)
+	}
+
+	return message.String()
+}
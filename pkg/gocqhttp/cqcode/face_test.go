@@ -0,0 +1,42 @@
+package cqcode
+
+import "testing"
+
+func TestShortcodeToFaces(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"早上好/微笑", "早上好[CQ:face,id=14]"},
+		{"/微笑/微笑", "[CQ:face,id=14][CQ:face,id=14]"},
+		{"/OK", "[CQ:face,id=124]"},
+		{"好的 /OK!", "好的 [CQ:face,id=124]!"},
+		{"好的/OK", "好的[CQ:face,id=124]"},
+
+		// 英文名称需要单词边界, 不匹配链接和路径
+		{"/NOTE and http://a/OK", "/NOTE and http://a/OK"},
+		{"/usr/local/doge", "/usr/local/doge"},
+		{"see /OKAY", "see /OKAY"},
+		{"a/OK", "a/OK"},
+
+		// 已有的CQ码和转义保持不变
+		{"[CQ:face,id=1]/微笑&#91;", "[CQ:face,id=1][CQ:face,id=14]&#91;"},
+	}
+
+	for _, test := range tests {
+		if got := ShortcodeToFaces(test.text); got != test.want {
+			t.Errorf("ShortcodeToFaces(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestFacesToShortcode(t *testing.T) {
+	text := "早上好[CQ:face,id=14][CQ:face,id=99999]"
+
+	if got, want := FacesToShortcode(text), "早上好/微笑[CQ:face,id=99999]"; got != want {
+		t.Errorf("FacesToShortcode(%q) = %q, want %q", text, got, want)
+	}
+
+	if got := ShortcodeToFaces(FacesToShortcode(text)); got != text {
+		t.Errorf("round trip = %q, want %q", got, text)
+	}
+}
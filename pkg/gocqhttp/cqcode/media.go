@@ -0,0 +1,168 @@
+package cqcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 媒体文件大小上限, 单位byte, 0为不限制
+var MediaLimit = map[string]int64{
+	"image":  30 << 20,
+	"record": 20 << 20,
+	"video":  100 << 20,
+}
+
+// 媒体文件
+type Media struct {
+	File string // file:// 或 base64:// 格式的文件
+	MIME string // 根据文件内容识别的MIME类型
+	Size int64  // 文件大小, 单位byte
+}
+
+// 本地文件
+//
+// * path 需要同时能被 go-cqhttp 访问, 相对路径按当前工作目录转换为绝对路径
+func MediaFile(path string) (*Media, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+
+	return &Media{
+		File: GetFileURL(path),
+		MIME: sniff(head[:n]),
+		Size: info.Size(),
+	}, nil
+}
+
+// 内存数据
+func MediaBytes(data []byte) *Media {
+	return &Media{
+		File: "base64://" + base64.StdEncoding.EncodeToString(data),
+		MIME: sniff(data),
+		Size: int64(len(data)),
+	}
+}
+
+// 读取数据
+//
+// * 读取量不超过 MediaLimit 中的最大值, 超出时返回错误
+func MediaReader(reader io.Reader) (*Media, error) {
+	var max int64
+	for _, limit := range MediaLimit {
+		if limit == 0 {
+			max = 0
+			break
+		}
+
+		if limit > max {
+			max = limit
+		}
+	}
+
+	if max > 0 {
+		reader = io.LimitReader(reader, max+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if max > 0 && int64(len(data)) > max {
+		return nil, fmt.Errorf("media exceeds %d bytes", max)
+	}
+
+	return MediaBytes(data), nil
+}
+
+// 识别MIME类型
+func sniff(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("#!SILK")), bytes.HasPrefix(head, []byte("\x02#!SILK")):
+		return "audio/silk"
+	case bytes.HasPrefix(head, []byte("#!AMR")):
+		return "audio/amr"
+	}
+
+	return http.DetectContentType(head)
+}
+
+// 检查文件类型和大小
+func (media *Media) check(types string) error {
+	if limit := MediaLimit[types]; limit > 0 && media.Size > limit {
+		return fmt.Errorf("%s exceeds %d bytes: %d", types, limit, media.Size)
+	}
+
+	var ok bool
+
+	switch types {
+	case "image":
+		ok = strings.HasPrefix(media.MIME, "image/")
+	case "record":
+		ok = strings.HasPrefix(media.MIME, "audio/") || strings.HasPrefix(media.MIME, "video/") || media.MIME == "application/ogg"
+	case "video":
+		ok = strings.HasPrefix(media.MIME, "video/")
+	}
+
+	if !ok {
+		return fmt.Errorf("unsupported %s type: %s", types, media.MIME)
+	}
+
+	return nil
+}
+
+// 图片
+func (media *Media) Image(flash bool) (string, error) {
+	err := media.check("image")
+	if err != nil {
+		return "", err
+	}
+
+	return Image(media.File, "", flash), nil
+}
+
+// 语音
+func (media *Media) Record(magic bool) (string, error) {
+	err := media.check("record")
+	if err != nil {
+		return "", err
+	}
+
+	return Record(media.File, "", magic), nil
+}
+
+// 短视频
+//
+// * 可选参数: cover
+func (media *Media) Video(cover string) (string, error) {
+	err := media.check("video")
+	if err != nil {
+		return "", err
+	}
+
+	return Video(media.File, cover), nil
+}
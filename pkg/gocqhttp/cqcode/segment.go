@@ -0,0 +1,270 @@
+package cqcode
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"koi/pkg/gocqhttp/qq"
+)
+
+// 消息段
+type Segment struct {
+	Type string            `json:"type"` // 类型, 纯文本为text
+	Data map[string]string `json:"data"` // 参数
+}
+
+// 消息
+type Message []Segment
+
+// 纯文本
+func Text(text string) string {
+	return Escape(text)
+}
+
+// 编码消息段
+func (segment Segment) String() string {
+	if segment.Type == "text" {
+		return Escape(segment.Data["text"])
+	}
+
+	data := make(map[string]interface{}, len(segment.Data))
+	for k, v := range segment.Data {
+		data[k] = v
+	}
+
+	return Encode(segment.Type, data)
+}
+
+// 编码消息
+func (message Message) String() string {
+	var builder strings.Builder
+
+	for _, segment := range message {
+		builder.WriteString(segment.String())
+	}
+
+	return builder.String()
+}
+
+// 解析消息
+func Parse(text string) (message Message) {
+	index := 0
+
+	for _, loc := range regex.FindAllStringIndex(text, -1) {
+		if loc[0] > index {
+			message = append(message, textSegment(text[index:loc[0]]))
+		}
+
+		function, data := Decode(text[loc[0]:loc[1]])
+		message = append(message, Segment{function, data})
+
+		index = loc[1]
+	}
+
+	if index < len(text) {
+		message = append(message, textSegment(text[index:]))
+	}
+
+	return message
+}
+
+func textSegment(text string) Segment {
+	return Segment{"text", map[string]string{"text": Unescape(text)}}
+}
+
+// 过滤消息段
+//
+// * 只保留纯文本和 allow 中列出的消息段类型, 其余CQ码将被移除
+//
+// * 用于处理不可信的用户输入, 如复读或转发用户消息
+func Sanitize(text string, allow ...string) string {
+	var message Message
+
+	for _, segment := range Parse(text) {
+		if segment.Type == "text" {
+			message = append(message, segment)
+			continue
+		}
+
+		for _, types := range allow {
+			if strings.EqualFold(segment.Type, types) {
+				message = append(message, segment)
+				break
+			}
+		}
+	}
+
+	return message.String()
+}
+
+// 获取指定类型的消息段
+func (message Message) Segments(types string) (segments Message) {
+	for _, segment := range message {
+		if strings.EqualFold(segment.Type, types) {
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments
+}
+
+// 纯文本内容
+//
+// * 不包含CQ码, 表情显示为名称, 如 [微笑]
+func (message Message) PlainText() string {
+	var builder strings.Builder
+
+	for _, segment := range message {
+		switch segment.Type {
+		case "text":
+			builder.WriteString(segment.Data["text"])
+		case "face":
+			id, _ := strconv.Atoi(segment.Data["id"])
+
+			builder.WriteString("[")
+			builder.WriteString(FaceName(id))
+			builder.WriteString("]")
+		}
+	}
+
+	return builder.String()
+}
+
+// 被@的QQ号
+//
+// * all: 是否@全体成员
+func (message Message) Mentions() (uids []qq.UserID, all bool) {
+	for _, segment := range message.Segments("at") {
+		id := segment.Data["qq"]
+
+		if id == "all" {
+			all = true
+			continue
+		}
+
+		uid, err := qq.ParseUserID(id)
+		if err == nil {
+			uids = append(uids, uid)
+		}
+	}
+
+	return uids, all
+}
+
+// 是否@了机器人
+func (message Message) MentionsSelf(self_id qq.UserID) bool {
+	uids, _ := message.Mentions()
+
+	for _, uid := range uids {
+		if uid == self_id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 去除开头@机器人的消息段
+//
+// * 同时去除回复消息段和@之后的空白, 如果消息不以@机器人开头则原样返回
+func (message Message) TrimSelfMention(self_id qq.UserID) Message {
+	index := 0
+
+	if index < len(message) && message[index].Type == "reply" {
+		index++
+	}
+
+	if index >= len(message) || message[index].Type != "at" || message[index].Data["qq"] != self_id.String() {
+		return message
+	}
+
+	rest := append(Message{}, message[:index]...)
+	rest = append(rest, message[index+1:]...)
+
+	if index < len(rest) && rest[index].Type == "text" {
+		text := strings.TrimLeft(rest[index].Data["text"], " \t\r\n")
+
+		if text == "" {
+			rest = append(rest[:index], rest[index+1:]...)
+		} else {
+			rest[index] = Segment{"text", map[string]string{"text": text}}
+		}
+	}
+
+	return rest
+}
+
+// 回复的消息ID
+func (message Message) ReplyID() (message_id qq.MessageID, ok bool) {
+	for _, segment := range message.Segments("reply") {
+		id, err := qq.ParseMessageID(segment.Data["id"])
+		if err == nil {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+// 图片URL
+//
+// * 没有url参数时返回file参数
+func (message Message) Images() []string {
+	return message.files("image")
+}
+
+// 语音URL
+//
+// * 没有url参数时返回file参数
+func (message Message) Records() []string {
+	return message.files("record")
+}
+
+func (message Message) files(types string) (files []string) {
+	for _, segment := range message.Segments(types) {
+		if url := segment.Data["url"]; url != "" {
+			files = append(files, url)
+		} else {
+			files = append(files, segment.Data["file"])
+		}
+	}
+
+	return files
+}
+
+// 解码消息段
+//
+// * 兼容参数值为数字或布尔值的消息段数组
+func (segment *Segment) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type string         `json:"type"`
+		Data map[string]any `json:"data"`
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&raw)
+	if err != nil {
+		return err
+	}
+
+	segment.Type = raw.Type
+	segment.Data = make(map[string]string, len(raw.Data))
+
+	for k, v := range raw.Data {
+		switch v := v.(type) {
+		case string:
+			segment.Data[k] = v
+		case nil:
+			segment.Data[k] = ""
+		default:
+			value, _ := json.Marshal(v)
+			segment.Data[k] = string(value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package cqcode
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// 分割选项
+type SplitOption struct {
+	MaxLength int // 每段最大长度, 按UTF-16码元计算, 与QQ计算消息长度的方式一致, 0为不限制
+	MaxBytes  int // 每段最大字节数, 按转义后的CQ码计算, 0为不限制
+}
+
+// 转义字符
+var entities = []string{"&amp;", "&#91;", "&#93;", "&#44;"}
+
+// 分割长消息
+//
+// * 不会在CQ码、转义字符、多字节字符或代理对内部分割, 优先在换行处分割
+//
+// * 转义字符按其表示的字符计算长度, CQ码按CQ码本身计算长度
+//
+// * 开头的回复和@消息段始终保留在第一段
+//
+// * 单个CQ码超出限制时单独成段, 开头的回复和@与其后第一个单元超出限制时不分割
+func Split(text string, option SplitOption) (parts []string) {
+	tokens, prefix := tokenize(text)
+
+	var current []string
+	var size length // 当前段的长度
+
+	for _, token := range tokens {
+		current = append(current, token)
+		size = size.add(measure(token), 1)
+
+		for len(current) > 1 && !option.fits(size) {
+			// 开头的回复和@不能单独成段
+			min := 1
+			if len(parts) == 0 {
+				min = prefix + 1
+			}
+
+			if len(current)-1 < min {
+				break
+			}
+
+			cut := len(current) - 1
+			for i := len(current) - 2; i >= min; i-- {
+				if current[i-1] == "\n" {
+					cut = i
+					break
+				}
+			}
+
+			for _, token := range current[:cut] {
+				size = size.add(measure(token), -1)
+			}
+
+			parts = append(parts, strings.Join(current[:cut], ""))
+			current = append([]string{}, current[cut:]...)
+		}
+	}
+
+	if len(current) > 0 {
+		parts = append(parts, strings.Join(current, ""))
+	}
+
+	return parts
+}
+
+// 消息长度
+type length struct {
+	units int // UTF-16码元数
+	bytes int // 字节数
+}
+
+// 加上或减去 n 倍的 other
+func (l length) add(other length, n int) length {
+	return length{l.units + n*other.units, l.bytes + n*other.bytes}
+}
+
+// 单元的长度
+func measure(token string) length {
+	l := length{bytes: len(token)}
+
+	if strings.HasPrefix(token, "&") && strings.HasSuffix(token, ";") {
+		l.units = 1
+		return l
+	}
+
+	for _, r := range token {
+		// 基本多文种平面以外的字符在UTF-16中为代理对
+		if r > 0xFFFF {
+			l.units += 2
+		} else {
+			l.units++
+		}
+	}
+
+	return l
+}
+
+func (option SplitOption) fits(size length) bool {
+	if option.MaxLength > 0 && size.units > option.MaxLength {
+		return false
+	}
+
+	if option.MaxBytes > 0 && size.bytes > option.MaxBytes {
+		return false
+	}
+
+	return true
+}
+
+// 将消息拆分为不可分割的单元
+//
+// * prefix: 开头回复和@消息段所占的单元数
+func tokenize(text string) (tokens []string, prefix int) {
+	leading := true
+
+	for _, segment := range Parse(text) {
+		if segment.Type != "text" {
+			tokens = append(tokens, segment.String())
+
+			if leading && (segment.Type == "reply" || segment.Type == "at") {
+				prefix = len(tokens)
+			} else {
+				leading = false
+			}
+
+			continue
+		}
+
+		escaped := segment.String()
+
+		for len(escaped) > 0 {
+			size := 0
+
+			for _, entity := range entities {
+				if strings.HasPrefix(escaped, entity) {
+					size = len(entity)
+					break
+				}
+			}
+
+			if size == 0 {
+				_, size = utf8.DecodeRuneInString(escaped)
+			}
+
+			tokens = append(tokens, escaped[:size])
+			escaped = escaped[size:]
+
+			if leading && strings.TrimSpace(tokens[len(tokens)-1]) == "" {
+				prefix = len(tokens)
+			} else {
+				leading = false
+			}
+		}
+	}
+
+	return tokens, prefix
+}
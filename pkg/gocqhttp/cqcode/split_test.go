@@ -0,0 +1,93 @@
+package cqcode
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		option SplitOption
+		want   []string
+	}{
+		{
+			name:   "不限制",
+			text:   "hello",
+			option: SplitOption{},
+			want:   []string{"hello"},
+		},
+		{
+			name:   "按长度分割",
+			text:   "abcdefg",
+			option: SplitOption{MaxLength: 3},
+			want:   []string{"abc", "def", "g"},
+		},
+		{
+			name:   "优先在换行处分割",
+			text:   "ab\ncdef",
+			option: SplitOption{MaxLength: 5},
+			want:   []string{"ab\n", "cdef"},
+		},
+		{
+			name:   "代理对按两个码元计算且不被分割",
+			text:   "a😀b😀",
+			option: SplitOption{MaxLength: 3},
+			want:   []string{"a😀", "b😀"},
+		},
+		{
+			name:   "不在CQ码内部分割",
+			text:   "ab[CQ:face,id=14]cd",
+			option: SplitOption{MaxLength: 4},
+			want:   []string{"ab", "[CQ:face,id=14]", "cd"},
+		},
+		{
+			name:   "转义字符按一个字符计算且不被分割",
+			text:   "a&#91;b&amp;c",
+			option: SplitOption{MaxLength: 2},
+			want:   []string{"a&#91;", "b&amp;", "c"},
+		},
+		{
+			name:   "按字节分割",
+			text:   "你好世界",
+			option: SplitOption{MaxBytes: 7},
+			want:   []string{"你好", "世界"},
+		},
+		{
+			name:   "回复和@只保留在第一段",
+			text:   "[CQ:reply,id=1][CQ:at,qq=2] abcdef",
+			option: SplitOption{MaxLength: 32},
+			want:   []string{"[CQ:reply,id=1][CQ:at,qq=2] abcd", "ef"},
+		},
+		{
+			name:   "回复和@不单独成段",
+			text:   "[CQ:reply,id=1]abc",
+			option: SplitOption{MaxLength: 5},
+			want:   []string{"[CQ:reply,id=1]a", "bc"},
+		},
+	}
+
+	for _, test := range tests {
+		got := Split(test.text, test.option)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Split(%q) = %q, want %q", test.name, test.text, got, test.want)
+		}
+
+		if joined := strings.Join(got, ""); joined != test.text {
+			t.Errorf("%s: parts join to %q, want %q", test.name, joined, test.text)
+		}
+	}
+}
+
+func TestSplitLinear(t *testing.T) {
+	text := strings.Repeat("字", 200000)
+
+	parts := Split(text, SplitOption{MaxLength: 1000})
+
+	if len(parts) != 200 {
+		t.Errorf("parts = %d, want 200", len(parts))
+	}
+}
@@ -0,0 +1,137 @@
+package event
+
+import (
+	"sync"
+
+	"koi/pkg/gocqhttp/cqcode"
+	"koi/pkg/gocqhttp/qq"
+)
+
+// 频道消息
+type GuildMessage struct {
+	Time        qq.Time      `json:"time"`         // 事件发生的时间戳
+	SelfID      qq.UserID    `json:"self_id"`      // 收到事件的机器人QQ号
+	SelfTinyID  qq.TinyID    `json:"self_tiny_id"` // 机器人在频道系统中的ID
+	PostType    string       `json:"post_type"`    // 上报类型
+	MessageType string       `json:"message_type"` // 消息类型
+	SubType     string       `json:"sub_type"`     // 消息子类型, 固定为channel
+	GuildID     qq.GuildID   `json:"guild_id"`     // 频道ID
+	ChannelID   qq.ChannelID `json:"channel_id"`   // 子频道ID
+	UserID      qq.TinyID    `json:"user_id"`      // 发送者频道用户ID
+	MessageID   string       `json:"message_id"`   // 消息ID
+	Message     string       `json:"message"`      // 消息内容
+	Sender      GuildSender  `json:"sender"`       // 发送人信息
+
+	segments_once sync.Once      // 保护消息段的首次解析
+	segments      cqcode.Message // 解析后的消息段, 首次访问时解析
+}
+
+// 频道消息发送人信息
+type GuildSender struct {
+	UserID   qq.TinyID `json:"user_id"`  // 发送者频道用户ID
+	TinyID   qq.TinyID `json:"tiny_id"`  // 发送者频道用户ID
+	Nickname string    `json:"nickname"` // 昵称
+}
+
+// 子频道消息撤回
+type GuildChannelRecall struct {
+	Time       qq.Time      `json:"time"`         // 事件发生的时间戳
+	SelfID     qq.UserID    `json:"self_id"`      // 收到事件的机器人QQ号
+	SelfTinyID qq.TinyID    `json:"self_tiny_id"` // 机器人在频道系统中的ID
+	PostType   string       `json:"post_type"`    // 上报类型
+	NoticeType string       `json:"notice_type"`  // 通知类型
+	GuildID    qq.GuildID   `json:"guild_id"`     // 频道ID
+	ChannelID  qq.ChannelID `json:"channel_id"`   // 子频道ID
+	UserID     qq.TinyID    `json:"user_id"`      // 消息发送者ID
+	OperatorID qq.TinyID    `json:"operator_id"`  // 操作者ID
+	MessageID  string       `json:"message_id"`   // 被撤回的消息ID
+}
+
+// 频道消息表情贴更新
+type MessageReactionsUpdated struct {
+	Time             qq.Time        `json:"time"`              // 事件发生的时间戳
+	SelfID           qq.UserID      `json:"self_id"`           // 收到事件的机器人QQ号
+	SelfTinyID       qq.TinyID      `json:"self_tiny_id"`      // 机器人在频道系统中的ID
+	PostType         string         `json:"post_type"`         // 上报类型
+	NoticeType       string         `json:"notice_type"`       // 通知类型
+	GuildID          qq.GuildID     `json:"guild_id"`          // 频道ID
+	ChannelID        qq.ChannelID   `json:"channel_id"`        // 子频道ID
+	UserID           qq.TinyID      `json:"user_id"`           // 操作者ID
+	MessageID        string         `json:"message_id"`        // 消息ID
+	CurrentReactions []ReactionInfo `json:"current_reactions"` // 当前消息被贴的表情列表
+}
+
+// 表情贴
+type ReactionInfo struct {
+	EmojiID    string `json:"emoji_id"`    // 表情ID
+	EmojiIndex int    `json:"emoji_index"` // 表情对应数值ID
+	EmojiType  int    `json:"emoji_type"`  // 表情类型
+	EmojiName  string `json:"emoji_name"`  // 表情名字
+	Count      int    `json:"count"`       // 当前表情被贴数量
+	Clicked    bool   `json:"clicked"`     // 机器人自身是否贴过该表情
+}
+
+// 子频道信息更新
+type ChannelUpdated struct {
+	Time       qq.Time      `json:"time"`         // 事件发生的时间戳
+	SelfID     qq.UserID    `json:"self_id"`      // 收到事件的机器人QQ号
+	SelfTinyID qq.TinyID    `json:"self_tiny_id"` // 机器人在频道系统中的ID
+	PostType   string       `json:"post_type"`    // 上报类型
+	NoticeType string       `json:"notice_type"`  // 通知类型
+	GuildID    qq.GuildID   `json:"guild_id"`     // 频道ID
+	ChannelID  qq.ChannelID `json:"channel_id"`   // 子频道ID
+	UserID     qq.TinyID    `json:"user_id"`      // 操作者ID
+	OperatorID qq.TinyID    `json:"operator_id"`  // 操作者ID
+	OldInfo    ChannelInfo  `json:"old_info"`     // 更新前的子频道信息
+	NewInfo    ChannelInfo  `json:"new_info"`     // 更新后的子频道信息
+}
+
+// 子频道创建
+type ChannelCreated struct {
+	Time        qq.Time      `json:"time"`         // 事件发生的时间戳
+	SelfID      qq.UserID    `json:"self_id"`      // 收到事件的机器人QQ号
+	SelfTinyID  qq.TinyID    `json:"self_tiny_id"` // 机器人在频道系统中的ID
+	PostType    string       `json:"post_type"`    // 上报类型
+	NoticeType  string       `json:"notice_type"`  // 通知类型
+	GuildID     qq.GuildID   `json:"guild_id"`     // 频道ID
+	ChannelID   qq.ChannelID `json:"channel_id"`   // 子频道ID
+	UserID      qq.TinyID    `json:"user_id"`      // 操作者ID
+	OperatorID  qq.TinyID    `json:"operator_id"`  // 操作者ID
+	ChannelInfo ChannelInfo  `json:"channel_info"` // 子频道信息
+}
+
+// 子频道删除
+type ChannelDestroyed struct {
+	Time        qq.Time      `json:"time"`         // 事件发生的时间戳
+	SelfID      qq.UserID    `json:"self_id"`      // 收到事件的机器人QQ号
+	SelfTinyID  qq.TinyID    `json:"self_tiny_id"` // 机器人在频道系统中的ID
+	PostType    string       `json:"post_type"`    // 上报类型
+	NoticeType  string       `json:"notice_type"`  // 通知类型
+	GuildID     qq.GuildID   `json:"guild_id"`     // 频道ID
+	ChannelID   qq.ChannelID `json:"channel_id"`   // 子频道ID
+	UserID      qq.TinyID    `json:"user_id"`      // 操作者ID
+	OperatorID  qq.TinyID    `json:"operator_id"`  // 操作者ID
+	ChannelInfo ChannelInfo  `json:"channel_info"` // 子频道信息
+}
+
+// 子频道信息
+type ChannelInfo struct {
+	OwnerGuildID    qq.GuildID     `json:"owner_guild_id"`    // 所属频道ID
+	ChannelID       qq.ChannelID   `json:"channel_id"`        // 子频道ID
+	ChannelType     int            `json:"channel_type"`      // 子频道类型, 1: 文字 2: 语音 5: 直播 7: 主题
+	ChannelName     string         `json:"channel_name"`      // 子频道名称
+	CreateTime      qq.Time        `json:"create_time"`       // 创建时间
+	CreatorTinyID   qq.TinyID      `json:"creator_tiny_id"`   // 创建者ID
+	TalkPermission  int            `json:"talk_permission"`   // 发言权限类型
+	VisibleType     int            `json:"visible_type"`      // 可视性类型
+	CurrentSlowMode int            `json:"current_slow_mode"` // 当前启用的慢速模式Key
+	SlowModes       []SlowModeInfo `json:"slow_modes"`        // 频道内可用慢速模式类型列表
+}
+
+// 慢速模式
+type SlowModeInfo struct {
+	SlowModeKey    int    `json:"slow_mode_key"`    // 慢速模式Key
+	SlowModeText   string `json:"slow_mode_text"`   // 慢速模式说明
+	SpeakFrequency int    `json:"speak_frequency"`  // 周期内发言频率限制
+	SlowModeCircle int    `json:"slow_mode_circle"` // 单位周期时间, 单位秒
+}
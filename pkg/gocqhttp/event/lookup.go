@@ -0,0 +1,138 @@
+package event
+
+import (
+	"encoding/json"
+
+	"koi/pkg/gocqhttp/schema"
+)
+
+// 事件类型
+type kind struct {
+	PostType      string `json:"post_type"`
+	MetaEventType string `json:"meta_event_type"`
+	MessageType   string `json:"message_type"`
+	RequestType   string `json:"request_type"`
+	NoticeType    string `json:"notice_type"`
+	SubType       string `json:"sub_type"`
+}
+
+// 根据上报类型创建事件
+//
+// * 返回对应事件结构体的指针, 未知事件返回nil
+//
+// * name: 事件类型名称, 如 message.group, notice.notify.poke
+func New(data []byte) (event any, name string, err error) {
+	var k kind
+
+	err = json.Unmarshal(data, &k)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch k.PostType {
+	case "meta_event":
+		name = "meta_event." + k.MetaEventType
+
+		switch k.MetaEventType {
+		case "lifecycle":
+			event = &Lifecycle{}
+		case "heartbeat":
+			event = &Heartbeat{}
+		}
+	case "message", "message_sent":
+		name = k.PostType + "." + k.MessageType
+
+		switch k.MessageType {
+		case "private":
+			event = &PrivateMessage{}
+		case "group":
+			event = &GroupMessage{}
+		case "guild":
+			event = &GuildMessage{}
+		}
+	case "request":
+		name = "request." + k.RequestType
+
+		switch k.RequestType {
+		case "friend":
+			event = &FriendRequest{}
+		case "group":
+			event = &GroupRequest{}
+		}
+	case "notice":
+		name = "notice." + k.NoticeType
+
+		switch k.NoticeType {
+		case "group_upload":
+			event = &GroupUpload{}
+		case "group_admin":
+			event = &GroupAdmin{}
+		case "group_decrease":
+			event = &GroupDecrease{}
+		case "group_increase":
+			event = &GroupIncrease{}
+		case "group_ban":
+			event = &GroupBan{}
+		case "friend_add":
+			event = &FriendAdd{}
+		case "group_recall":
+			event = &GroupRecall{}
+		case "friend_recall":
+			event = &FriendRecall{}
+		case "group_card":
+			event = &GroupCard{}
+		case "offline_file":
+			event = &OfflineFile{}
+		case "client_status":
+			event = &ClientStatus{}
+		case "essence":
+			event = &EssenceMessage{}
+		case "guild_channel_recall":
+			event = &GuildChannelRecall{}
+		case "message_reactions_updated":
+			event = &MessageReactionsUpdated{}
+		case "channel_updated":
+			event = &ChannelUpdated{}
+		case "channel_created":
+			event = &ChannelCreated{}
+		case "channel_destroyed":
+			event = &ChannelDestroyed{}
+		case "notify":
+			name += "." + k.SubType
+
+			switch k.SubType {
+			case "poke":
+				event = &Poke{}
+			case "lucky_king":
+				event = &LuckyKing{}
+			case "honor":
+				event = &Honor{}
+			case "title":
+				event = &Title{}
+			}
+		}
+	default:
+		name = k.PostType
+	}
+
+	return event, name, nil
+}
+
+// 解码事件
+//
+// * 返回已解码的事件结构体指针, 未知事件返回nil
+//
+// * 通过 schema.Unmarshal 解码, 严格模式下会报告与结构体不一致的字段
+func Decode(data []byte) (event any, name string, err error) {
+	event, name, err = New(data)
+	if err != nil || event == nil {
+		return nil, name, err
+	}
+
+	err = schema.Unmarshal(data, event)
+	if err != nil {
+		return nil, name, err
+	}
+
+	return event, name, nil
+}
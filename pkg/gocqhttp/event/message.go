@@ -0,0 +1,133 @@
+package event
+
+import (
+	"sync"
+
+	"koi/pkg/gocqhttp/cqcode"
+	"koi/pkg/gocqhttp/qq"
+)
+
+// 解析消息段, 结果缓存在 cache 中
+//
+// * 每个事件使用各自的 once, 不同事件的解析互不阻塞
+func parse(once *sync.Once, cache *cqcode.Message, text string) cqcode.Message {
+	once.Do(func() {
+		*cache = cqcode.Parse(text)
+	})
+
+	return *cache
+}
+
+// 消息段
+//
+// * 首次调用时解析, 之后返回缓存结果, 可在多个处理器间共享
+func (message *PrivateMessage) Segments() cqcode.Message {
+	return parse(&message.segments_once, &message.segments, message.Message)
+}
+
+// 纯文本内容
+func (message *PrivateMessage) PlainText() string {
+	return message.Segments().PlainText()
+}
+
+// 被@的QQ号
+//
+// * all: 是否@全体成员
+func (message *PrivateMessage) Mentions() (uids []qq.UserID, all bool) {
+	return message.Segments().Mentions()
+}
+
+// 是否@了机器人
+func (message *PrivateMessage) MentionsSelf() bool {
+	return message.Segments().MentionsSelf(message.SelfID)
+}
+
+// 回复的消息ID
+//
+// * 不是回复消息时 ok 为false
+func (message *PrivateMessage) ReplyID() (message_id qq.MessageID, ok bool) {
+	return message.Segments().ReplyID()
+}
+
+// 消息段
+//
+// * 首次调用时解析, 之后返回缓存结果, 可在多个处理器间共享
+func (message *GroupMessage) Segments() cqcode.Message {
+	return parse(&message.segments_once, &message.segments, message.Message)
+}
+
+// 纯文本内容
+func (message *GroupMessage) PlainText() string {
+	return message.Segments().PlainText()
+}
+
+// 被@的QQ号
+//
+// * all: 是否@全体成员
+func (message *GroupMessage) Mentions() (uids []qq.UserID, all bool) {
+	return message.Segments().Mentions()
+}
+
+// 是否@了机器人
+func (message *GroupMessage) MentionsSelf() bool {
+	return message.Segments().MentionsSelf(message.SelfID)
+}
+
+// 回复的消息ID
+//
+// * 不是回复消息时 ok 为false
+func (message *GroupMessage) ReplyID() (message_id qq.MessageID, ok bool) {
+	return message.Segments().ReplyID()
+}
+
+// 消息段
+//
+// * 首次调用时解析, 之后返回缓存结果, 可在多个处理器间共享
+func (message *GuildMessage) Segments() cqcode.Message {
+	return parse(&message.segments_once, &message.segments, message.Message)
+}
+
+// 纯文本内容
+func (message *GuildMessage) PlainText() string {
+	return message.Segments().PlainText()
+}
+
+// 被@的频道用户ID
+//
+// * all: 是否@全体成员
+func (message *GuildMessage) Mentions() (uids []qq.TinyID, all bool) {
+	for _, segment := range message.Segments().Segments("at") {
+		if segment.Data["qq"] == "all" {
+			all = true
+			continue
+		}
+
+		uids = append(uids, qq.TinyID(segment.Data["qq"]))
+	}
+
+	return uids, all
+}
+
+// 是否@了机器人
+func (message *GuildMessage) MentionsSelf() bool {
+	uids, _ := message.Mentions()
+
+	for _, uid := range uids {
+		if uid == message.SelfTinyID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 回复的消息ID
+//
+// * 不是回复消息时 ok 为false
+func (message *GuildMessage) ReplyID() (message_id string, ok bool) {
+	for _, segment := range message.Segments().Segments("reply") {
+		return segment.Data["id"], true
+	}
+
+	return "", false
+}
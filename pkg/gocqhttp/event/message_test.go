@@ -0,0 +1,43 @@
+package event
+
+import (
+	"sync"
+	"testing"
+
+	"koi/pkg/gocqhttp/qq"
+)
+
+func TestSegmentsConcurrent(t *testing.T) {
+	message := &GroupMessage{
+		SelfID:  10,
+		Message: "[CQ:reply,id=5][CQ:at,qq=10] 你好&#91;",
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if got := len(message.Segments()); got != 3 {
+				t.Errorf("segments = %d, want 3", got)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if !message.MentionsSelf() {
+		t.Error("MentionsSelf = false")
+	}
+
+	if id, ok := message.ReplyID(); !ok || id != qq.MessageID(5) {
+		t.Errorf("ReplyID = %v, %v", id, ok)
+	}
+
+	if got := message.PlainText(); got != " 你好[" {
+		t.Errorf("PlainText = %q", got)
+	}
+}
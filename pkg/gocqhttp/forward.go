@@ -0,0 +1,175 @@
+package gocqhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"koi/pkg/gocqhttp/cqcode"
+	"koi/pkg/gocqhttp/qq"
+	"koi/pkg/gocqhttp/schema"
+
+	"github.com/gorilla/websocket"
+)
+
+// 引用消息节点
+func ForwardNode(message_id qq.MessageID) ForwardMessage {
+	return ForwardMessage{ID: message_id}
+}
+
+// 自定义消息节点
+func ForwardCustom(name string, uin qq.UserID, content string) ForwardMessage {
+	return ForwardMessage{Name: name, Uin: uin, Content: content}
+}
+
+// 消息段数组节点
+func ForwardSegments(name string, uin qq.UserID, segments cqcode.Message) ForwardMessage {
+	return ForwardMessage{Name: name, Uin: uin, Segments: segments}
+}
+
+// 嵌套合并转发节点
+func ForwardNested(name string, uin qq.UserID, nodes ...ForwardMessage) ForwardMessage {
+	return ForwardMessage{Name: name, Uin: uin, Nodes: nodes}
+}
+
+// 编码转发节点数据
+func (message ForwardMessage) MarshalJSON() ([]byte, error) {
+	if message.ID != 0 {
+		return json.Marshal(forward_msg_id{message.ID})
+	}
+
+	type data struct {
+		Name    string    `json:"name"`
+		Uin     qq.UserID `json:"uin"`
+		Content any       `json:"content"`
+		Seq     string    `json:"seq,omitempty"`
+		Time    int64     `json:"time,omitempty"`
+	}
+
+	var content any = message.Content
+
+	switch {
+	case len(message.Nodes) > 0:
+		content = forwardNodes(message.Nodes)
+	case len(message.Segments) > 0:
+		content = message.Segments
+	}
+
+	var time int64
+	if !message.Time.IsZero() {
+		time = message.Time.Unix()
+	}
+
+	return json.Marshal(data{message.Name, message.Uin, content, message.Seq, time})
+}
+
+// 转换为转发节点
+func forwardNodes(messages []ForwardMessage) (contents []forward_message_custom) {
+	for _, message := range messages {
+		contents = append(contents, forward_message_custom{
+			Type: "node",
+			Data: message,
+		})
+	}
+
+	return contents
+}
+
+// 发送合并转发消息 (私聊)
+//
+// * 节点可以混合使用引用消息、自定义消息和嵌套转发
+func SendPrivateForwardMessage(ws *websocket.Conn, user_id qq.UserID, messages ...ForwardMessage) (message_id qq.MessageID, err error) {
+	return SendPrivateForwardMessageContext(context.Background(), ws, user_id, messages...)
+}
+
+// 发送合并转发消息 (私聊)
+//
+// * 节点可以混合使用引用消息、自定义消息和嵌套转发
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SendPrivateForwardMessageContext(ctx context.Context, ws *websocket.Conn, user_id qq.UserID, messages ...ForwardMessage) (message_id qq.MessageID, err error) {
+	return sendForwardData(ctx, ws, user_id, 0, forwardNodes(messages))
+}
+
+// 发送合并转发消息 (群)
+//
+// * 节点可以混合使用引用消息、自定义消息和嵌套转发
+func SendGroupForwardMessage(ws *websocket.Conn, group_id qq.GroupID, messages ...ForwardMessage) (message_id qq.MessageID, err error) {
+	return SendGroupForwardMessageContext(context.Background(), ws, group_id, messages...)
+}
+
+// 发送合并转发消息 (群)
+//
+// * 节点可以混合使用引用消息、自定义消息和嵌套转发
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SendGroupForwardMessageContext(ctx context.Context, ws *websocket.Conn, group_id qq.GroupID, messages ...ForwardMessage) (message_id qq.MessageID, err error) {
+	return sendForwardData(ctx, ws, 0, group_id, forwardNodes(messages))
+}
+
+// 获取合并转发内容
+//
+// * 内容中嵌套的合并转发将被一并获取并填入 Nodes
+func GetForwardMessage(ws *websocket.Conn, forward_id string) (messages []ForwardMessage, err error) {
+	type params struct {
+		MessageID string `json:"message_id"`
+	}
+
+	type node struct {
+		Content json.RawMessage `json:"content"` // 消息内容
+		Sender  MessageSender   `json:"sender"`  // 发送者信息
+		Time    qq.Time         `json:"time"`    // 发送时间
+	}
+
+	var forward struct {
+		Messages []node `json:"messages"`
+	}
+
+	var message = ws_data{
+		Action: "get_forward_msg",
+		Params: params{forward_id},
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.Unmarshal(data, &forward)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range forward.Messages {
+		message := ForwardMessage{
+			Name: node.Sender.Nickname,
+			Uin:  node.Sender.UserID,
+			Time: node.Time,
+		}
+
+		// 消息内容可能是字符串或消息段数组
+		if bytes.HasPrefix(bytes.TrimSpace(node.Content), []byte("[")) {
+			err = json.Unmarshal(node.Content, &message.Segments)
+			message.Content = message.Segments.String()
+		} else {
+			err = json.Unmarshal(node.Content, &message.Content)
+			message.Segments = cqcode.Parse(message.Content)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, segment := range message.Segments.Segments("forward") {
+			nodes, err := GetForwardMessage(ws, segment.Data["id"])
+			if err != nil {
+				return nil, err
+			}
+
+			message.Nodes = append(message.Nodes, nodes...)
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,402 @@
+package gocqhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	localpath "path/filepath"
+	"strings"
+	"time"
+
+	"koi/pkg/gocqhttp/qq"
+
+	"github.com/gorilla/websocket"
+)
+
+// 群文件系统中的条目
+type GroupFileEntry struct {
+	Path   string  // 以/开头的完整路径
+	Folder *Folder // 文件夹, 为文件时为nil
+	File   *File   // 文件, 为文件夹时为nil
+}
+
+// 是否为文件夹
+func (entry GroupFileEntry) IsDir() bool {
+	return entry.Folder != nil
+}
+
+// 列出文件夹内容
+//
+// * folder_id 为空时列出根目录
+func listGroupFiles(ws *websocket.Conn, group_id qq.GroupID, folder_id string) (GroupFiles, error) {
+	if folder_id == "" || folder_id == "/" {
+		return GetGroupRootFiles(ws, group_id)
+	}
+
+	return GetGroupFilesByFolder(ws, group_id, folder_id)
+}
+
+// 遍历群文件
+//
+// * 从根目录开始深度优先遍历, 文件夹先于其中的内容传给 fn
+//
+// * fn 对文件夹返回 fs.SkipDir 时跳过该文件夹, 返回其他错误时停止遍历
+func WalkGroupFiles(ws *websocket.Conn, group_id qq.GroupID, fn func(entry GroupFileEntry) error) error {
+	err := walkGroupFiles(ws, group_id, "", "/", fn)
+	if err == fs.SkipDir {
+		return nil
+	}
+
+	return err
+}
+
+func walkGroupFiles(ws *websocket.Conn, group_id qq.GroupID, folder_id, dir string, fn func(entry GroupFileEntry) error) error {
+	files, err := listGroupFiles(ws, group_id, folder_id)
+	if err != nil {
+		return err
+	}
+
+	for i := range files.Files {
+		err = fn(GroupFileEntry{Path: path.Join(dir, files.Files[i].FileName), File: &files.Files[i]})
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range files.Folders {
+		folder := &files.Folders[i]
+		entry := GroupFileEntry{Path: path.Join(dir, folder.FolderName), Folder: folder}
+
+		err = fn(entry)
+		if err == fs.SkipDir {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		err = walkGroupFiles(ws, group_id, folder.FolderID, entry.Path, fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 根据路径查找群文件或文件夹
+//
+// * 如 /releases/v2/app.zip, 根目录为 /
+//
+// * 不存在时返回的错误满足 errors.Is(err, fs.ErrNotExist)
+func StatGroupFile(ws *websocket.Conn, group_id qq.GroupID, name string) (GroupFileEntry, error) {
+	name = path.Clean("/" + name)
+
+	if name == "/" {
+		return GroupFileEntry{Path: "/", Folder: &Folder{GroupID: group_id, FolderID: "/", FolderName: "/"}}, nil
+	}
+
+	folder_id := ""
+	parts := strings.Split(strings.TrimPrefix(name, "/"), "/")
+
+	for i, part := range parts {
+		files, err := listGroupFiles(ws, group_id, folder_id)
+		if err != nil {
+			return GroupFileEntry{}, err
+		}
+
+		current := "/" + strings.Join(parts[:i+1], "/")
+		last := i == len(parts)-1
+		found := false
+
+		for j := range files.Folders {
+			if files.Folders[j].FolderName == part {
+				if last {
+					return GroupFileEntry{Path: current, Folder: &files.Folders[j]}, nil
+				}
+
+				folder_id = files.Folders[j].FolderID
+				found = true
+				break
+			}
+		}
+
+		if last {
+			for j := range files.Files {
+				if files.Files[j].FileName == part {
+					return GroupFileEntry{Path: current, File: &files.Files[j]}, nil
+				}
+			}
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	return GroupFileEntry{}, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// 下载群文件时, 连续无数据的最长时间, 超过后放弃下载
+var download_idle = 30 * time.Second
+
+// 下载群文件使用的客户端, 不限制总时长, 大文件的下载时间由 download_idle 和 ctx 控制
+var download_client = &http.Client{
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+	},
+}
+
+// 下载群文件到本地
+//
+// * 本地文件已存在且大小相同时跳过
+//
+// * 超过 download_idle 没有收到数据时放弃下载, 需要限制总时长时使用 DownloadGroupFileContext
+func DownloadGroupFile(ws *websocket.Conn, group_id qq.GroupID, file File, local string) error {
+	return DownloadGroupFileContext(context.Background(), ws, group_id, file, local)
+}
+
+// 下载群文件到本地
+//
+// * ctx 用于取消下载, 取消后不会留下不完整的文件
+func DownloadGroupFileContext(ctx context.Context, ws *websocket.Conn, group_id qq.GroupID, file File, local string) error {
+	if info, err := os.Stat(local); err == nil && uint(info.Size()) == file.FileSize {
+		return nil
+	}
+
+	url, err := GetGroupFileURL(ws, group_id, file.FileID, file.Busid)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := download_client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("download %s: %s", file.FileName, response.Status)
+	}
+
+	err = os.MkdirAll(localpath.Dir(local), 0755)
+	if err != nil {
+		return err
+	}
+
+	// 先写入临时文件, 避免中断后留下不完整的文件
+	temp, err := os.CreateTemp(localpath.Dir(local), "."+localpath.Base(local)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	_, err = io.Copy(temp, &idle_reader{reader: response.Body, timer: time.AfterFunc(download_idle, cancel)})
+	if err != nil {
+		temp.Close()
+		return fmt.Errorf("download %s: %w", file.FileName, err)
+	}
+
+	err = temp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), local)
+}
+
+// 每次读到数据时重置计时器, 计时器到期时取消下载
+type idle_reader struct {
+	reader io.Reader
+	timer  *time.Timer
+}
+
+func (r *idle_reader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	if n > 0 {
+		r.timer.Reset(download_idle)
+	}
+	if err != nil {
+		r.timer.Stop()
+	}
+	return n, err
+}
+
+// 下载群文件夹到本地目录
+//
+// * folder: 群文件夹路径, 根目录为 /
+//
+// * 保留目录结构, 本地已存在且大小相同的文件跳过
+func DownloadGroupFolder(ws *websocket.Conn, group_id qq.GroupID, folder, dir string) error {
+	return DownloadGroupFolderContext(context.Background(), ws, group_id, folder, dir)
+}
+
+// 下载群文件夹到本地目录
+//
+// * ctx 用于取消下载, 已下载完成的文件会保留
+func DownloadGroupFolderContext(ctx context.Context, ws *websocket.Conn, group_id qq.GroupID, folder, dir string) error {
+	root, err := StatGroupFile(ws, group_id, folder)
+	if err != nil {
+		return err
+	}
+
+	if !root.IsDir() {
+		return DownloadGroupFileContext(ctx, ws, group_id, *root.File, localpath.Join(dir, path.Base(root.Path)))
+	}
+
+	return walkGroupFiles(ws, group_id, root.Folder.FolderID, root.Path, func(entry GroupFileEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel := strings.TrimPrefix(strings.TrimPrefix(entry.Path, root.Path), "/")
+
+		return DownloadGroupFileContext(ctx, ws, group_id, *entry.File, localpath.Join(dir, localpath.FromSlash(rel)))
+	})
+}
+
+// 同步本地目录到群文件夹
+//
+// * folder: 群文件夹路径, 根目录为 /
+//
+// * 创建缺少的文件夹, 包括 folder 本身, 群文件夹中已有同名且大小相同的文件时跳过
+//
+// * QQ群文件只支持一级文件夹, 因此只有同步到根目录时才会同步本地的子目录, 更深的子目录将被忽略
+//
+// * dir 需要同时能被 go-cqhttp 访问, 返回上传的文件路径
+func SyncGroupFolder(ws *websocket.Conn, group_id qq.GroupID, dir, folder string) (uploaded []string, err error) {
+	dir, err = localpath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := StatGroupFile(ws, group_id, folder)
+	if errors.Is(err, fs.ErrNotExist) {
+		root, err = createGroupFolder(ws, group_id, folder)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !root.IsDir() {
+		return nil, &fs.PathError{Op: "sync", Path: root.Path, Err: fmt.Errorf("not a folder")}
+	}
+
+	return syncGroupFolder(ws, group_id, dir, root, root.Path == "/")
+}
+
+// 创建群文件夹
+//
+// * 只能在根目录下创建
+func createGroupFolder(ws *websocket.Conn, group_id qq.GroupID, folder string) (GroupFileEntry, error) {
+	folder = path.Clean("/" + folder)
+
+	if path.Dir(folder) != "/" {
+		return GroupFileEntry{}, &fs.PathError{Op: "mkdir", Path: folder, Err: fmt.Errorf("only folders under the root can be created")}
+	}
+
+	err := CreateGroupFileFolder(ws, group_id, path.Base(folder))
+	if err != nil {
+		return GroupFileEntry{}, err
+	}
+
+	return StatGroupFile(ws, group_id, folder)
+}
+
+func syncGroupFolder(ws *websocket.Conn, group_id qq.GroupID, dir string, folder GroupFileEntry, recursive bool) (uploaded []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := listGroupFiles(ws, group_id, folder.Folder.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]uint, len(files.Files))
+	for _, file := range files.Files {
+		sizes[file.FileName] = file.FileSize
+	}
+
+	folders := make(map[string]Folder, len(files.Folders))
+	for _, f := range files.Folders {
+		folders[f.FolderName] = f
+	}
+
+	for _, entry := range entries {
+		local := localpath.Join(dir, entry.Name())
+
+		if entry.IsDir() {
+			if !recursive {
+				continue
+			}
+
+			sub, ok := folders[entry.Name()]
+			if !ok {
+				created, err := createGroupFolder(ws, group_id, entry.Name())
+				if err != nil {
+					return uploaded, err
+				}
+
+				sub = *created.Folder
+			}
+
+			files, err := syncGroupFolder(ws, group_id, local, GroupFileEntry{Path: path.Join(folder.Path, sub.FolderName), Folder: &sub}, false)
+			uploaded = append(uploaded, files...)
+			if err != nil {
+				return uploaded, err
+			}
+
+			continue
+		}
+
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return uploaded, err
+		}
+
+		if size, ok := sizes[entry.Name()]; ok && size == uint(info.Size()) {
+			continue
+		}
+
+		folder_id := folder.Folder.FolderID
+		if folder_id == "/" {
+			folder_id = ""
+		}
+
+		err = UploadGroupFile(ws, group_id, local, entry.Name(), folder_id)
+		if err != nil {
+			return uploaded, err
+		}
+
+		uploaded = append(uploaded, path.Join(folder.Path, entry.Name()))
+	}
+
+	return uploaded, nil
+}
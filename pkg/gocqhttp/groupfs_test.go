@@ -0,0 +1,98 @@
+package gocqhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	localpath "path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// 启动文件服务器, 并让 get_group_file_url 返回它的地址
+//
+// * 文件服务器写出 body 后保持连接, 直到请求被取消
+func newFileServer(t *testing.T, body string, stall bool) *websocket.Conn {
+	t.Helper()
+
+	files := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+		w.(http.Flusher).Flush()
+
+		if stall {
+			<-r.Context().Done()
+		}
+	}))
+	t.Cleanup(files.Close)
+
+	_, ws := newFakeServer(t, func(action string, _ json.RawMessage) map[string]any {
+		return ok(map[string]any{"url": files.URL})
+	})
+
+	return ws
+}
+
+func TestDownloadGroupFile(t *testing.T) {
+	ws := newFileServer(t, "hello", false)
+	local := localpath.Join(t.TempDir(), "dir", "a.txt")
+
+	err := DownloadGroupFile(ws, 1, File{FileID: "a", FileName: "a.txt", FileSize: 5}, local)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := os.ReadFile(local); err != nil || string(data) != "hello" {
+		t.Errorf("file = %q, %v, want hello", data, err)
+	}
+}
+
+func TestDownloadGroupFileIdle(t *testing.T) {
+	saved := download_idle
+	download_idle = 50 * time.Millisecond
+	t.Cleanup(func() { download_idle = saved })
+
+	ws := newFileServer(t, "partial", true)
+	dir := t.TempDir()
+	local := localpath.Join(dir, "a.txt")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- DownloadGroupFile(ws, 1, File{FileID: "a", FileName: "a.txt", FileSize: 100}, local)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("stalled download succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stalled download did not give up")
+	}
+
+	// 不留下不完整的文件
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("left %d files after failed download", len(entries))
+	}
+}
+
+func TestDownloadGroupFileContext(t *testing.T) {
+	ws := newFileServer(t, "partial", true)
+	local := localpath.Join(t.TempDir(), "a.txt")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := DownloadGroupFileContext(ctx, ws, 1, File{FileID: "a", FileName: "a.txt", FileSize: 100}, local)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	if _, err := os.Stat(local); !os.IsNotExist(err) {
+		t.Errorf("local file exists after cancelled download")
+	}
+}
@@ -0,0 +1,374 @@
+package gocqhttp
+
+import (
+	"context"
+
+	"koi/pkg/gocqhttp/cqcode"
+	"koi/pkg/gocqhttp/event"
+	"koi/pkg/gocqhttp/qq"
+	"koi/pkg/gocqhttp/schema"
+
+	"github.com/gorilla/websocket"
+)
+
+// 频道系统内的账号资料
+type GuildServiceProfile struct {
+	Nickname  string    `json:"nickname"`   // 昵称
+	TinyID    qq.TinyID `json:"tiny_id"`    // 自身的频道用户ID
+	AvatarURL string    `json:"avatar_url"` // 头像URL
+}
+
+// 获取频道系统内BOT的资料
+func GetGuildServiceProfile(ws *websocket.Conn) (profile GuildServiceProfile, err error) {
+	var message = ws_data{Action: "get_guild_service_profile"}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return GuildServiceProfile{}, err
+	}
+
+	err = schema.Unmarshal(data, &profile)
+	if err != nil {
+		return GuildServiceProfile{}, err
+	}
+
+	return profile, err
+}
+
+// 频道
+type Guild struct {
+	GuildID        qq.GuildID `json:"guild_id"`         // 频道ID
+	GuildName      string     `json:"guild_name"`       // 频道名称
+	GuildDisplayID string     `json:"guild_display_id"` // 频道显示ID, 公测后可能被废除
+}
+
+// 获取频道列表
+func GetGuildList(ws *websocket.Conn) (list []Guild, err error) {
+	var message = ws_data{Action: "get_guild_list"}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.Unmarshal(data, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+// 频道元数据
+type GuildMeta struct {
+	GuildID        qq.GuildID `json:"guild_id"`         // 频道ID
+	GuildName      string     `json:"guild_name"`       // 频道名称
+	GuildProfile   string     `json:"guild_profile"`    // 频道简介
+	CreateTime     qq.Time    `json:"create_time"`      // 创建时间
+	MaxMemberCount int        `json:"max_member_count"` // 频道人数上限
+	MaxRobotCount  int        `json:"max_robot_count"`  // 频道BOT数上限
+	MaxAdminCount  int        `json:"max_admin_count"`  // 频道管理员人数上限
+	MemberCount    int        `json:"member_count"`     // 已加入人数
+	OwnerID        qq.TinyID  `json:"owner_id"`         // 创建者ID
+}
+
+// 通过访客获取频道元数据
+func GetGuildMetaByGuest(ws *websocket.Conn, guild_id qq.GuildID) (meta GuildMeta, err error) {
+	type params struct {
+		GuildID qq.GuildID `json:"guild_id"`
+	}
+
+	var message = ws_data{
+		Action: "get_guild_meta_by_guest",
+		Params: params{guild_id},
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return GuildMeta{}, err
+	}
+
+	err = schema.Unmarshal(data, &meta)
+	if err != nil {
+		return GuildMeta{}, err
+	}
+
+	return meta, err
+}
+
+// 获取子频道列表
+//
+// * no_cache: 是否无视缓存
+func GetGuildChannelList(ws *websocket.Conn, guild_id qq.GuildID, no_cache bool) (list []event.ChannelInfo, err error) {
+	type params struct {
+		GuildID qq.GuildID `json:"guild_id"`
+		NoCache bool       `json:"no_cache"`
+	}
+
+	var message = ws_data{
+		Action: "get_guild_channel_list",
+		Params: params{guild_id, no_cache},
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.Unmarshal(data, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, err
+}
+
+// 频道成员列表
+type GuildMemberList struct {
+	Members   []GuildMember `json:"members"`    // 成员列表
+	Finished  bool          `json:"finished"`   // 是否最终页
+	NextToken string        `json:"next_token"` // 翻页Token
+}
+
+// 频道成员
+type GuildMember struct {
+	TinyID   qq.TinyID `json:"tiny_id"`   // 成员ID
+	Title    string    `json:"title"`     // 成员头衔
+	Nickname string    `json:"nickname"`  // 成员昵称
+	RoleID   string    `json:"role_id"`   // 所在权限组ID
+	RoleName string    `json:"role_name"` // 所在权限组名称
+}
+
+// 获取频道成员列表
+//
+// * 可选参数: next_token
+//
+// * 首次调用不传 next_token, 之后传入上一页返回的 NextToken 直到 Finished 为true
+func GetGuildMemberList(ws *websocket.Conn, guild_id qq.GuildID, next_token string) (list GuildMemberList, err error) {
+	type params struct {
+		GuildID   qq.GuildID `json:"guild_id"`
+		NextToken string     `json:"next_token,omitempty"`
+	}
+
+	var message = ws_data{
+		Action: "get_guild_member_list",
+		Params: params{guild_id, next_token},
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return GuildMemberList{}, err
+	}
+
+	err = schema.Unmarshal(data, &list)
+	if err != nil {
+		return GuildMemberList{}, err
+	}
+
+	return list, err
+}
+
+// 频道成员资料
+type GuildMemberProfile struct {
+	TinyID    qq.TinyID   `json:"tiny_id"`    // 用户ID
+	Nickname  string      `json:"nickname"`   // 用户昵称
+	AvatarURL string      `json:"avatar_url"` // 头像URL
+	JoinTime  qq.Time     `json:"join_time"`  // 加入时间
+	Roles     []GuildRole `json:"roles"`      // 所在的身份组, 仅包含 RoleID 和 RoleName
+}
+
+// 单独获取频道成员信息
+func GetGuildMemberProfile(ws *websocket.Conn, guild_id qq.GuildID, user_id qq.TinyID) (profile GuildMemberProfile, err error) {
+	type params struct {
+		GuildID qq.GuildID `json:"guild_id"`
+		UserID  qq.TinyID  `json:"user_id"`
+	}
+
+	var message = ws_data{
+		Action: "get_guild_member_profile",
+		Params: params{guild_id, user_id},
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return GuildMemberProfile{}, err
+	}
+
+	err = schema.Unmarshal(data, &profile)
+	if err != nil {
+		return GuildMemberProfile{}, err
+	}
+
+	return profile, err
+}
+
+// 发送频道消息
+func SendGuildChannelMessage(ws *websocket.Conn, guild_id qq.GuildID, channel_id qq.ChannelID, text string) (message_id string, err error) {
+	return SendGuildChannelMessageContext(context.Background(), ws, guild_id, channel_id, text)
+}
+
+// 发送频道消息
+//
+// * ctx 用于取消调用和指定单次调用的选项, 见 WithVariant 和 WithPriority
+func SendGuildChannelMessageContext(ctx context.Context, ws *websocket.Conn, guild_id qq.GuildID, channel_id qq.ChannelID, text string) (message_id string, err error) {
+	type params struct {
+		GuildID   qq.GuildID   `json:"guild_id"`
+		ChannelID qq.ChannelID `json:"channel_id"`
+		Message   string       `json:"message"`
+	}
+
+	var msg struct {
+		ID string `json:"message_id"`
+	}
+
+	var message = ws_data{
+		Action: "send_guild_channel_msg",
+		Params: params{guild_id, channel_id, text},
+	}
+
+	data, err := message.doContext(ctx, ws)
+	if err != nil {
+		return "", err
+	}
+
+	err = schema.Unmarshal(data, &msg)
+	if err != nil {
+		return "", err
+	}
+
+	return msg.ID, nil
+}
+
+// 发送频道纯文本消息
+//
+// * 消息内容不会被解析为CQ码
+func SendGuildChannelText(ws *websocket.Conn, guild_id qq.GuildID, channel_id qq.ChannelID, text string) (message_id string, err error) {
+	return SendGuildChannelMessage(ws, guild_id, channel_id, cqcode.Escape(text))
+}
+
+// 频道身份组
+type GuildRole struct {
+	RoleID      string `json:"role_id"`      // 身份组ID
+	RoleName    string `json:"role_name"`    // 身份组名称
+	ARGBColor   uint32 `json:"argb_color"`   // ARGB颜色
+	Independent bool   `json:"independent"`  // 是否独立显示
+	MemberCount int    `json:"member_count"` // 成员数
+	MaxCount    int    `json:"max_count"`    // 成员数上限
+	Owned       bool   `json:"owned"`        // 机器人是否拥有该身份组
+	Disabled    bool   `json:"disabled"`     // 是否已禁用
+}
+
+// 获取频道身份组列表
+func GetGuildRoles(ws *websocket.Conn, guild_id qq.GuildID) (roles []GuildRole, err error) {
+	type params struct {
+		GuildID qq.GuildID `json:"guild_id"`
+	}
+
+	var message = ws_data{
+		Action: "get_guild_roles",
+		Params: params{guild_id},
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.Unmarshal(data, &roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, err
+}
+
+// 创建频道身份组
+//
+// * color: ARGB颜色
+//
+// * initial_users: 创建后加入身份组的成员
+func CreateGuildRole(ws *websocket.Conn, guild_id qq.GuildID, name string, color uint32, independent bool, initial_users []qq.TinyID) (role_id string, err error) {
+	type params struct {
+		GuildID      qq.GuildID  `json:"guild_id"`
+		Name         string      `json:"name"`
+		Color        uint32      `json:"color"`
+		Independent  bool        `json:"independent"`
+		InitialUsers []qq.TinyID `json:"initial_users"`
+	}
+
+	var role struct {
+		RoleID string `json:"role_id"`
+	}
+
+	var message = ws_data{
+		Action: "create_guild_role",
+		Params: params{guild_id, name, color, independent, initial_users},
+	}
+
+	data, err := message.do(ws)
+	if err != nil {
+		return "", err
+	}
+
+	err = schema.Unmarshal(data, &role)
+	if err != nil {
+		return "", err
+	}
+
+	return role.RoleID, nil
+}
+
+// 修改频道身份组
+func UpdateGuildRole(ws *websocket.Conn, guild_id qq.GuildID, role_id, name string, color uint32, independent bool) error {
+	type params struct {
+		GuildID     qq.GuildID `json:"guild_id"`
+		RoleID      string     `json:"role_id"`
+		Name        string     `json:"name"`
+		Color       uint32     `json:"color"`
+		Independent bool       `json:"indepedent"` // go-cqhttp 的参数名拼写如此
+	}
+
+	var message = ws_data{
+		Action: "update_guild_role",
+		Params: params{guild_id, role_id, name, color, independent},
+	}
+
+	_, err := message.do(ws)
+	return err
+}
+
+// 删除频道身份组
+func DeleteGuildRole(ws *websocket.Conn, guild_id qq.GuildID, role_id string) error {
+	type params struct {
+		GuildID qq.GuildID `json:"guild_id"`
+		RoleID  string     `json:"role_id"`
+	}
+
+	var message = ws_data{
+		Action: "delete_guild_role",
+		Params: params{guild_id, role_id},
+	}
+
+	_, err := message.do(ws)
+	return err
+}
+
+// 设置用户在频道中的身份组
+//
+// * set: true为设置, false为取消
+func SetGuildMemberRole(ws *websocket.Conn, guild_id qq.GuildID, role_id string, set bool, users []qq.TinyID) error {
+	type params struct {
+		GuildID qq.GuildID  `json:"guild_id"`
+		RoleID  string      `json:"role_id"`
+		Set     bool        `json:"set"`
+		Users   []qq.TinyID `json:"users"`
+	}
+
+	var message = ws_data{
+		Action: "set_guild_member_role",
+		Params: params{guild_id, role_id, set, users},
+	}
+
+	_, err := message.do(ws)
+	return err
+}
@@ -0,0 +1,149 @@
+package gocqhttp
+
+import (
+	"sort"
+	"time"
+
+	"koi/pkg/gocqhttp/event"
+	"koi/pkg/gocqhttp/qq"
+
+	"github.com/gorilla/websocket"
+)
+
+// 群消息历史记录选项
+type HistoryOption struct {
+	Before uint      // 从该序号开始向前获取, 包括该消息, 0为从最新消息开始
+	After  uint      // 只获取序号大于该值的消息, 0为不限制
+	Since  time.Time // 只获取该时间之后的消息, 零值为不限制
+	Until  time.Time // 只获取该时间之前的消息, 零值为不限制
+	Limit  int       // 最多获取的条数, 0为不限制
+}
+
+// 群消息历史记录迭代器
+//
+// * 从新到旧逐条返回消息, 自动翻页并去除重复的消息
+type HistoryIterator struct {
+	ws       *websocket.Conn
+	group_id qq.GroupID
+	option   HistoryOption
+
+	page    []event.GroupMessage // 当前页中尚未返回的消息, 从旧到新
+	seq     uint                 // 下一页的起始序号
+	oldest  int                  // 已返回的最旧消息序号, -1表示尚未返回消息
+	count   int                  // 已返回的条数
+	message *event.GroupMessage
+	fetched bool // 是否已获取过
+	done    bool
+	err     error
+}
+
+// 遍历群消息历史记录
+//
+//	history := gocqhttp.GroupHistory(ws, group_id, gocqhttp.HistoryOption{Limit: 100})
+//	for history.Next() {
+//		message := history.Message()
+//	}
+//	err := history.Err()
+func GroupHistory(ws *websocket.Conn, group_id qq.GroupID, option HistoryOption) *HistoryIterator {
+	return &HistoryIterator{
+		ws:       ws,
+		group_id: group_id,
+		option:   option,
+		seq:      option.Before,
+		oldest:   -1,
+	}
+}
+
+// 前进到下一条消息
+//
+// * 没有更多消息或出错时返回false
+func (history *HistoryIterator) Next() bool {
+	history.message = nil
+
+	if history.done {
+		return false
+	}
+
+	if history.option.Limit > 0 && history.count >= history.option.Limit {
+		history.done = true
+		return false
+	}
+
+	for {
+		for len(history.page) > 0 {
+			last := len(history.page) - 1
+			message := &history.page[last]
+			history.page = history.page[:last]
+
+			// 跳过相邻页重叠的消息
+			if history.oldest >= 0 && message.MessageSeq >= history.oldest {
+				continue
+			}
+
+			if history.option.Before > 0 && message.MessageSeq > int(history.option.Before) {
+				continue
+			}
+
+			history.oldest = message.MessageSeq
+
+			if (history.option.After > 0 && message.MessageSeq <= int(history.option.After)) ||
+				(!history.option.Since.IsZero() && message.Time.Before(history.option.Since)) {
+				history.done = true
+				return false
+			}
+
+			if !history.option.Until.IsZero() && message.Time.After(history.option.Until) {
+				continue
+			}
+
+			history.count++
+			history.message = message
+
+			return true
+		}
+
+		if !history.fetch() {
+			history.done = true
+			return false
+		}
+	}
+}
+
+// 获取下一页
+func (history *HistoryIterator) fetch() bool {
+	messages, err := GetGroupMessageHistory(history.ws, history.seq, history.group_id)
+	if err != nil {
+		history.err = err
+		return false
+	}
+
+	if len(messages) == 0 {
+		return false
+	}
+
+	sort.Slice(messages, func(i, j int) bool { return messages[i].MessageSeq < messages[j].MessageSeq })
+
+	// 没有翻到更旧的消息时结束
+	oldest := uint(messages[0].MessageSeq)
+	if history.fetched && oldest >= history.seq {
+		return false
+	}
+
+	history.fetched = true
+	history.page = messages
+	history.seq = oldest
+
+	return true
+}
+
+// 当前消息
+//
+// * 消息段在首次访问时解析
+func (history *HistoryIterator) Message() *event.GroupMessage {
+	return history.message
+}
+
+// 遍历中出现的错误
+func (history *HistoryIterator) Err() error {
+	return history.err
+}
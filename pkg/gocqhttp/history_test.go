@@ -0,0 +1,145 @@
+package gocqhttp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var history_base = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// 模拟群消息历史记录, 序号为 1 到 total, 每分钟一条
+//
+// * 每页返回起始序号及之前的 size 条消息, 包括起始序号, 因此相邻页重叠一条, 页内顺序打乱
+func historyServer(t *testing.T, total, size int) *websocket.Conn {
+	_, ws := newFakeServer(t, func(action string, params json.RawMessage) map[string]any {
+		var p struct {
+			Seq int `json:"message_seq"`
+		}
+		json.Unmarshal(params, &p)
+
+		if p.Seq == 0 || p.Seq > total {
+			p.Seq = total
+		}
+
+		var messages []map[string]any
+		for seq := p.Seq; seq > p.Seq-size && seq > 0; seq-- {
+			message := map[string]any{
+				"post_type":    "message",
+				"message_type": "group",
+				"group_id":     1,
+				"message_seq":  seq,
+				"message_id":   seq,
+				"time":         history_base.Add(time.Duration(seq) * time.Minute).Unix(),
+			}
+
+			if seq%2 == 0 {
+				messages = append(messages, message)
+			} else {
+				messages = append([]map[string]any{message}, messages...)
+			}
+		}
+
+		return ok(map[string]any{"messages": messages})
+	})
+
+	return ws
+}
+
+// 遍历并返回消息序号
+func sequences(t *testing.T, history *HistoryIterator) (seqs []int) {
+	t.Helper()
+
+	for history.Next() {
+		seqs = append(seqs, history.Message().MessageSeq)
+	}
+
+	if err := history.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return seqs
+}
+
+// 从 from 递减到 to 的序号
+func descending(from, to int) (seqs []int) {
+	for seq := from; seq >= to; seq-- {
+		seqs = append(seqs, seq)
+	}
+
+	return seqs
+}
+
+func TestHistoryOverlap(t *testing.T) {
+	history := GroupHistory(historyServer(t, 45, 20), 1, HistoryOption{})
+
+	// 相邻页重叠的消息只返回一次
+	if got, want := sequences(t, history), descending(45, 1); !reflect.DeepEqual(got, want) {
+		t.Errorf("seqs = %v, want %v", got, want)
+	}
+}
+
+func TestHistoryOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		option HistoryOption
+		want   []int
+	}{
+		{"limit", HistoryOption{Limit: 25}, descending(45, 21)},
+		{"before", HistoryOption{Before: 30}, descending(30, 1)},
+		{"after", HistoryOption{After: 10}, descending(45, 11)},
+		{"since", HistoryOption{Since: history_base.Add(40 * time.Minute)}, descending(45, 40)},
+		{"until", HistoryOption{Until: history_base.Add(5 * time.Minute)}, descending(5, 1)},
+		{"before after limit", HistoryOption{Before: 40, After: 5, Limit: 100}, descending(40, 6)},
+	}
+
+	for _, test := range tests {
+		history := GroupHistory(historyServer(t, 45, 20), 1, test.option)
+
+		if got := sequences(t, history); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: seqs = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestHistoryStuck(t *testing.T) {
+	// 总是返回同一页时结束, 不会无限翻页
+	_, ws := newFakeServer(t, func(string, json.RawMessage) map[string]any {
+		return ok(map[string]any{"messages": []map[string]any{
+			{"post_type": "message", "message_type": "group", "group_id": 1, "message_seq": 2},
+			{"post_type": "message", "message_type": "group", "group_id": 1, "message_seq": 1},
+		}})
+	})
+
+	if got := sequences(t, GroupHistory(ws, 1, HistoryOption{})); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Errorf("seqs = %v, want [2 1]", got)
+	}
+}
+
+func TestHistoryError(t *testing.T) {
+	pages := 0
+
+	_, ws := newFakeServer(t, func(string, json.RawMessage) map[string]any {
+		pages++
+		if pages > 1 {
+			return failed("MESSAGES_API_ERROR", "获取消息失败")
+		}
+
+		return ok(map[string]any{"messages": []map[string]any{
+			{"post_type": "message", "message_type": "group", "group_id": 1, "message_seq": 10},
+		}})
+	})
+
+	history := GroupHistory(ws, 1, HistoryOption{})
+
+	if !history.Next() || history.Message().MessageSeq != 10 {
+		t.Fatal("first message missing")
+	}
+
+	if history.Next() || history.Err() == nil {
+		t.Errorf("Next after failed page = true or Err = nil")
+	}
+}
@@ -0,0 +1,196 @@
+package gocqhttp
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"koi/pkg/gocqhttp/event"
+	"koi/pkg/gocqhttp/qq"
+)
+
+// 群荣誉变更记录
+type HonorRecord struct {
+	Time      time.Time    // 变更时间
+	GroupID   qq.GroupID   // 群号
+	UserID    qq.UserID    // 获得荣誉的成员QQ号
+	HonorType qq.HonorType // 荣誉类型
+}
+
+// 群荣誉统计
+type HonorCount struct {
+	UserID qq.UserID // 成员QQ号
+	Count  int       // 获得次数
+}
+
+// 群荣誉记录器
+//
+// * 通过 Handle 接收群荣誉变更事件, 记录只保存在内存中, 可通过 Records 和 Load 持久化
+//
+// * 记录按时间排序保存, 超过保留时长的记录会被丢弃
+type HonorTracker struct {
+	mu      sync.RWMutex
+	keep    time.Duration
+	records []HonorRecord
+}
+
+// 创建群荣誉记录器
+//
+// * keep: 记录的保留时长, 如 90 * 24 * time.Hour, 不大于0时永久保留
+func NewHonorTracker(keep time.Duration) *HonorTracker {
+	return &HonorTracker{keep: keep}
+}
+
+// 处理事件
+//
+// * 接收 go-cqhttp 上报的原始事件, 群荣誉变更以外的事件将被忽略
+func (tracker *HonorTracker) Handle(data []byte) error {
+	e, _, err := event.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	honor, ok := e.(*event.Honor)
+	if !ok {
+		return nil
+	}
+
+	tracker.Record(honor)
+
+	return nil
+}
+
+// 记录群荣誉变更事件
+func (tracker *HonorTracker) Record(honor *event.Honor) {
+	at := honor.Time.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	tracker.Load([]HonorRecord{{
+		Time:      at,
+		GroupID:   honor.GroupID,
+		UserID:    honor.UserID,
+		HonorType: honor.HonorType,
+	}})
+}
+
+// 导入记录
+//
+// * 超过保留时长的记录会被忽略
+func (tracker *HonorTracker) Load(records []HonorRecord) {
+	records = append([]HonorRecord(nil), records...)
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.records = merge(tracker.records, records)
+	tracker.expire(time.Now())
+}
+
+// 合并两组按时间排序的记录, 时间相同时 a 中的记录在前
+//
+// * 新记录通常晚于已有记录, 此时直接追加
+func merge(a, b []HonorRecord) []HonorRecord {
+	if len(b) == 0 {
+		return a
+	}
+
+	if len(a) == 0 || !b[0].Time.Before(a[len(a)-1].Time) {
+		return append(a, b...)
+	}
+
+	// 早于 b[0] 的记录保持不动
+	i := sort.Search(len(a), func(i int) bool { return a[i].Time.After(b[0].Time) })
+
+	merged := make([]HonorRecord, i, len(a)+len(b))
+	copy(merged, a[:i])
+
+	a = a[i:]
+	for len(a) > 0 && len(b) > 0 {
+		if b[0].Time.Before(a[0].Time) {
+			merged, b = append(merged, b[0]), b[1:]
+		} else {
+			merged, a = append(merged, a[0]), a[1:]
+		}
+	}
+
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
+
+// 丢弃超过保留时长的记录
+func (tracker *HonorTracker) expire(now time.Time) {
+	if tracker.keep <= 0 {
+		return
+	}
+
+	cutoff := now.Add(-tracker.keep)
+
+	i := sort.Search(len(tracker.records), func(i int) bool {
+		return !tracker.records[i].Time.Before(cutoff)
+	})
+
+	// 被丢弃的部分在追加记录重新分配时释放
+	tracker.records = tracker.records[i:]
+}
+
+// 获取记录
+//
+// * 按时间排序, 包括 since, 不包括 until, 零值为不限制
+//
+// * types 为 qq.HonorAll 时返回所有类型
+func (tracker *HonorTracker) Records(group_id qq.GroupID, types qq.HonorType, since, until time.Time) (records []HonorRecord) {
+	tracker.mu.RLock()
+	defer tracker.mu.RUnlock()
+
+	i := sort.Search(len(tracker.records), func(i int) bool {
+		return !tracker.records[i].Time.Before(since)
+	})
+
+	for _, record := range tracker.records[i:] {
+		if !until.IsZero() && !record.Time.Before(until) {
+			break
+		}
+
+		if record.GroupID != group_id || (types != qq.HonorAll && record.HonorType != types) {
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// 统计获得荣誉的次数
+//
+// * 按次数从多到少排序, 如本月获得龙王最多的成员:
+//
+//	now := time.Now()
+//	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+//	top := tracker.Top(group_id, qq.HonorTalkative, month, time.Time{})
+func (tracker *HonorTracker) Top(group_id qq.GroupID, types qq.HonorType, since, until time.Time) (counts []HonorCount) {
+	index := make(map[qq.UserID]int)
+
+	for _, record := range tracker.Records(group_id, types, since, until) {
+		i, ok := index[record.UserID]
+		if !ok {
+			i = len(counts)
+			index[record.UserID] = i
+			counts = append(counts, HonorCount{UserID: record.UserID})
+		}
+
+		counts[i].Count++
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		return counts[i].Count > counts[j].Count
+	})
+
+	return counts
+}
@@ -0,0 +1,54 @@
+package gocqhttp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"koi/pkg/gocqhttp/qq"
+)
+
+func TestHonorTrackerOrder(t *testing.T) {
+	tracker := NewHonorTracker(0)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	at := func(day int, user_id qq.UserID) HonorRecord {
+		return HonorRecord{Time: base.AddDate(0, 0, day), GroupID: 1, UserID: user_id, HonorType: qq.HonorTalkative}
+	}
+
+	tracker.Load([]HonorRecord{at(3, 3), at(1, 1)})
+	tracker.Load([]HonorRecord{at(5, 5)})
+	tracker.Load([]HonorRecord{at(4, 4), at(0, 0), at(2, 2)})
+	tracker.Load([]HonorRecord{at(3, 6)})
+
+	var users []qq.UserID
+	for _, record := range tracker.Records(1, qq.HonorAll, time.Time{}, time.Time{}) {
+		users = append(users, record.UserID)
+	}
+
+	// 时间相同时先导入的在前
+	if want := []qq.UserID{0, 1, 2, 3, 6, 4, 5}; !reflect.DeepEqual(users, want) {
+		t.Errorf("users = %v, want %v", users, want)
+	}
+
+	records := tracker.Records(1, qq.HonorAll, base.AddDate(0, 0, 2), base.AddDate(0, 0, 4))
+	if len(records) != 3 || records[0].UserID != 2 || records[2].UserID != 6 {
+		t.Errorf("records in [2, 4) = %v", records)
+	}
+}
+
+func TestHonorTrackerExpire(t *testing.T) {
+	tracker := NewHonorTracker(24 * time.Hour)
+	now := time.Now()
+
+	tracker.Load([]HonorRecord{
+		{Time: now.Add(-48 * time.Hour), GroupID: 1, UserID: 1, HonorType: qq.HonorTalkative},
+		{Time: now.Add(-time.Hour), GroupID: 1, UserID: 2, HonorType: qq.HonorTalkative},
+	})
+	tracker.Load([]HonorRecord{{Time: now, GroupID: 1, UserID: 2, HonorType: qq.HonorTalkative}})
+
+	top := tracker.Top(1, qq.HonorTalkative, time.Time{}, time.Time{})
+	if want := []HonorCount{{UserID: 2, Count: 2}}; !reflect.DeepEqual(top, want) {
+		t.Errorf("Top = %v, want %v", top, want)
+	}
+}
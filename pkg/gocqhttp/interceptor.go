@@ -0,0 +1,260 @@
+package gocqhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"koi/pkg/log"
+)
+
+// 调用处理函数
+//
+// * 返回API响应中的 data 部分
+type Handler func(ctx context.Context, action string, params any) (json.RawMessage, error)
+
+// 拦截器
+//
+// * 可以观察或修改调用, 不调用 next 时直接以返回值作为调用结果
+type Interceptor func(ctx context.Context, action string, params any, next Handler) (json.RawMessage, error)
+
+// 拦截器列表
+//
+// * 按顺序包裹所有API调用, 第一个位于最外层
+//
+// * 应在发起调用前设置, 运行中修改不是并发安全的
+var Interceptors []Interceptor
+
+// 调用耗时
+type timing struct {
+	mu    sync.Mutex
+	trips int           // 收发次数, 重试时大于1, 被拦截器直接返回时为0
+	trip  time.Duration // 累计收发耗时
+}
+
+// 记录一次收发
+func (t *timing) add(trip time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.trips++
+	t.trip += trip
+}
+
+// 分别返回收发耗时和等待时间
+//
+// * 等待时间包括排队, 限速和重试间隔, 没有实际收发时全部计为收发耗时
+func (t *timing) split(total time.Duration) (trip, wait time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.trips == 0 {
+		return total, 0
+	}
+
+	return t.trip, total - t.trip
+}
+
+type timingKey struct{}
+
+// 在 ctx 中记录调用耗时
+//
+// * 外层已经记录时共用同一个记录
+func withTiming(ctx context.Context) (context.Context, *timing) {
+	if t := timingOf(ctx); t != nil {
+		return ctx, t
+	}
+
+	t := new(timing)
+
+	return context.WithValue(ctx, timingKey{}, t), t
+}
+
+func timingOf(ctx context.Context) *timing {
+	t, _ := ctx.Value(timingKey{}).(*timing)
+	return t
+}
+
+// 调用日志拦截器
+//
+// * 以 DEBUG 等级记录每次调用的终结点, 参数, 收发耗时, 等待时间和错误
+func LogCalls(ctx context.Context, action string, params any, next Handler) (json.RawMessage, error) {
+	ctx, t := withTiming(ctx)
+	start := time.Now()
+
+	data, err := next(ctx, action, params)
+
+	trip, wait := t.split(time.Since(start))
+	args, _ := json.Marshal(params)
+
+	if err != nil {
+		log.Debug(fmt.Sprintf("action=%s params=%s duration=%s wait=%s error=%q", action, args, trip, wait, err))
+	} else {
+		log.Debug(fmt.Sprintf("action=%s params=%s duration=%s wait=%s", action, args, trip, wait))
+	}
+
+	return data, err
+}
+
+// 调用耗时直方图
+//
+// * 只统计收发耗时, 排队, 限速和重试间隔的等待时间单独累计
+type LatencyHistogram struct {
+	mu      sync.Mutex
+	bounds  []time.Duration
+	actions map[string]*Latency
+}
+
+// 单个终结点的耗时统计
+type Latency struct {
+	Bounds []time.Duration // 各区间的上界, 最后一个区间没有上界
+	Counts []uint64        // 各区间的调用次数, 比 Bounds 多一个
+	Total  time.Duration   // 累计收发耗时, 重试时为各次之和
+	Wait   time.Duration   // 累计等待时间, 包括排队, 限速和重试间隔
+	Errors uint64          // 失败次数
+}
+
+// 调用次数
+func (latency Latency) Calls() (calls uint64) {
+	for _, count := range latency.Counts {
+		calls += count
+	}
+
+	return calls
+}
+
+// 创建耗时直方图
+//
+// * bounds: 各区间的上界, 为空时使用默认区间
+func NewLatencyHistogram(bounds ...time.Duration) *LatencyHistogram {
+	if len(bounds) == 0 {
+		bounds = []time.Duration{
+			10 * time.Millisecond,
+			50 * time.Millisecond,
+			100 * time.Millisecond,
+			500 * time.Millisecond,
+			time.Second,
+			5 * time.Second,
+		}
+	}
+
+	bounds = append([]time.Duration(nil), bounds...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	return &LatencyHistogram{
+		bounds:  bounds,
+		actions: make(map[string]*Latency),
+	}
+}
+
+// 拦截器
+func (histogram *LatencyHistogram) Intercept(ctx context.Context, action string, params any, next Handler) (json.RawMessage, error) {
+	ctx, t := withTiming(ctx)
+	start := time.Now()
+
+	data, err := next(ctx, action, params)
+
+	trip, wait := t.split(time.Since(start))
+	histogram.observe(action, trip, wait, err != nil)
+
+	return data, err
+}
+
+func (histogram *LatencyHistogram) observe(action string, duration, wait time.Duration, failed bool) {
+	histogram.mu.Lock()
+	defer histogram.mu.Unlock()
+
+	latency := histogram.actions[action]
+	if latency == nil {
+		latency = &Latency{
+			Bounds: histogram.bounds,
+			Counts: make([]uint64, len(histogram.bounds)+1),
+		}
+		histogram.actions[action] = latency
+	}
+
+	index := sort.Search(len(histogram.bounds), func(i int) bool { return duration <= histogram.bounds[i] })
+
+	latency.Counts[index]++
+	latency.Total += duration
+	latency.Wait += wait
+
+	if failed {
+		latency.Errors++
+	}
+}
+
+// 获取各终结点的耗时统计
+func (histogram *LatencyHistogram) Snapshot() map[string]Latency {
+	histogram.mu.Lock()
+	defer histogram.mu.Unlock()
+
+	snapshot := make(map[string]Latency, len(histogram.actions))
+
+	for action, latency := range histogram.actions {
+		copied := *latency
+		copied.Counts = append([]uint64(nil), latency.Counts...)
+		snapshot[action] = copied
+	}
+
+	return snapshot
+}
+
+// 演练模式中被拦截的调用
+type DryRunCall struct {
+	Time   time.Time // 调用时间
+	Action string    // API终结点
+	Params any       // 参数
+}
+
+// 演练模式
+//
+// * 记录发送消息和管理操作而不实际执行, 其他调用照常执行
+type DryRun struct {
+	mu    sync.Mutex
+	calls []DryRunCall
+}
+
+// 是否为发送消息或管理操作
+func mutating(action string) bool {
+	if limited[action] {
+		return true
+	}
+
+	for _, prefix := range []string{"set_", "delete_", "send_", "upload_", "create_", "update_", "_send_", "_del_", "_set_", "mark_"} {
+		if strings.HasPrefix(action, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 拦截器
+func (dry *DryRun) Intercept(ctx context.Context, action string, params any, next Handler) (json.RawMessage, error) {
+	if !mutating(action) {
+		return next(ctx, action, params)
+	}
+
+	dry.mu.Lock()
+	dry.calls = append(dry.calls, DryRunCall{time.Now(), action, params})
+	dry.mu.Unlock()
+
+	if limited[action] {
+		return json.RawMessage(`{"message_id":""}`), nil
+	}
+
+	return json.RawMessage("null"), nil
+}
+
+// 获取被拦截的调用
+func (dry *DryRun) Calls() []DryRunCall {
+	dry.mu.Lock()
+	defer dry.mu.Unlock()
+
+	return append([]DryRunCall(nil), dry.calls...)
+}
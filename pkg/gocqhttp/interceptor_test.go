@@ -0,0 +1,72 @@
+package gocqhttp
+
+import (
+	"testing"
+	"time"
+)
+
+// 设置测试期间的拦截器
+func withInterceptors(t *testing.T, interceptors ...Interceptor) {
+	saved := Interceptors
+	Interceptors = interceptors
+	t.Cleanup(func() { Interceptors = saved })
+}
+
+func TestLatencyHistogramExcludesWait(t *testing.T) {
+	histogram := NewLatencyHistogram()
+	withInterceptors(t, histogram.Intercept)
+
+	_, ws := newFakeServer(t, nil)
+
+	Configure(ws, Options{Limit: LimitPolicy{Group: Rate{Every: 100 * time.Millisecond, Burst: 1}}})
+
+	for i := 0; i < 2; i++ {
+		if _, err := SendGroupText(ws, 1, "hello"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latency := histogram.Snapshot()["send_msg"]
+
+	if latency.Calls() != 2 {
+		t.Fatalf("calls = %d, want 2", latency.Calls())
+	}
+
+	// 第二条消息等待限速约100ms, 不计入收发耗时
+	if latency.Wait < 50*time.Millisecond {
+		t.Errorf("wait = %s, want about 100ms", latency.Wait)
+	}
+
+	if latency.Total >= 50*time.Millisecond {
+		t.Errorf("total = %s, want round-trip time only", latency.Total)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dry := new(DryRun)
+	histogram := NewLatencyHistogram()
+	withInterceptors(t, histogram.Intercept, dry.Intercept)
+
+	server, ws := newFakeServer(t, nil)
+
+	if err := SetGroupBan(ws, 1, 2, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := GetStatus(ws); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := dry.Calls(); len(calls) != 1 || calls[0].Action != "set_group_ban" {
+		t.Errorf("dry-run calls = %+v", calls)
+	}
+
+	if got := server.Actions(); len(got) != 1 || got[0] != "get_status" {
+		t.Errorf("actions = %v, want only get_status", got)
+	}
+
+	// 被拦截的调用没有等待时间
+	if latency := histogram.Snapshot()["set_group_ban"]; latency.Calls() != 1 || latency.Wait != 0 {
+		t.Errorf("set_group_ban latency = %+v", latency)
+	}
+}
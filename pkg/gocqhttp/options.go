@@ -0,0 +1,78 @@
+package gocqhttp
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// 连接设置
+//
+// * 通过 Configure 设置, 未设置的连接使用零值
+type Options struct {
+	// 各API终结点默认的调用方式
+	//
+	// * 如 map[string]string{"send_msg": VariantRateLimited}, 未设置的终结点同步调用
+	//
+	// * 异步和限速调用不返回执行结果, 对应函数的返回值均为零值
+	Variants map[string]string
+
+	// 各API终结点默认的优先级
+	//
+	// * 未设置的终结点使用内置的默认值, 管理操作为 PriorityHigh, 其余为 PriorityNormal
+	Priorities map[string]int
+
+	// 发送消息的限速策略
+	//
+	// * 如 LimitPolicy{Group: Rate{Every: time.Second, Burst: 3}}
+	Limit LimitPolicy
+
+	// 调用被取消后等待迟到响应的时间, 0为30秒
+	//
+	// * 取消的调用仍占用连接直到收到响应, 超时后连接将被关闭, 避免阻塞之后的调用
+	LateReply time.Duration
+
+	// 重连
+	//
+	// * 连接读写失败后, 下一次调用前通过该函数建立新连接, 之后的调用使用新连接
+	//
+	// * 为nil时读写失败的连接会被关闭, 之后的调用均失败, 读写失败也不会重试
+	Dial func() (*websocket.Conn, error)
+}
+
+// 设置连接
+//
+// * 对之后发起的调用生效, options 会被复制, 之后修改其中的 map 不影响连接
+//
+// * 设置随 Close 一同清除
+func Configure(ws *websocket.Conn, options Options) {
+	options.Variants = clone(options.Variants)
+	options.Priorities = clone(options.Priorities)
+
+	c := getConn(ws)
+
+	c.mu.Lock()
+	c.options = options
+	c.mu.Unlock()
+}
+
+// 获取连接设置
+func (c *conn) config() Options {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.options
+}
+
+func clone[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+
+	copied := make(map[K]V, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+
+	return copied
+}
@@ -0,0 +1,56 @@
+package qq
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// 频道ID
+type GuildID string
+
+// 子频道ID
+type ChannelID string
+
+// 频道用户ID
+type TinyID string
+
+// 解码频道相关ID
+//
+// * 兼容数字和字符串两种编码, 统一转换为字符串
+func unmarshalString(data []byte) (string, error) {
+	data = bytes.TrimSpace(data)
+
+	if bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+
+		err := json.Unmarshal(data, &text)
+		return text, err
+	}
+
+	var number json.Number
+
+	err := json.Unmarshal(data, &number)
+	return number.String(), err
+}
+
+func (id *GuildID) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalString(data)
+	*id = GuildID(value)
+	return err
+}
+
+func (id *ChannelID) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalString(data)
+	*id = ChannelID(value)
+	return err
+}
+
+func (id *TinyID) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalString(data)
+	*id = TinyID(value)
+	return err
+}
@@ -0,0 +1,31 @@
+package qq
+
+// 群荣誉类型
+type HonorType string
+
+const (
+	HonorTalkative    HonorType = "talkative"     // 龙王
+	HonorPerformer    HonorType = "performer"     // 群聊之火
+	HonorLegend       HonorType = "legend"        // 群聊炽焰
+	HonorStrongNewbie HonorType = "strong_newbie" // 冒尖小春笋
+	HonorEmotion      HonorType = "emotion"       // 快乐之源
+	HonorAll          HonorType = "all"           // 所有类型, 仅用于查询
+)
+
+// 群荣誉名称
+func (honor HonorType) Name() string {
+	switch honor {
+	case HonorTalkative:
+		return "龙王"
+	case HonorPerformer:
+		return "群聊之火"
+	case HonorLegend:
+		return "群聊炽焰"
+	case HonorStrongNewbie:
+		return "冒尖小春笋"
+	case HonorEmotion:
+		return "快乐之源"
+	}
+
+	return string(honor)
+}
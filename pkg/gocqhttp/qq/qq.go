@@ -0,0 +1,112 @@
+package qq
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// QQ号
+type UserID int64
+
+// 群号
+type GroupID int64
+
+// 消息ID
+type MessageID int64
+
+// 读取数字
+//
+// * 兼容数字和字符串两种编码, 返回数字的文本, null 和空字符串返回空文本
+func number(data []byte) (string, error) {
+	data = bytes.TrimSpace(data)
+
+	if bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+
+		err := json.Unmarshal(data, &text)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(text), nil
+	}
+
+	return string(data), nil
+}
+
+// 解码ID
+//
+// * 兼容数字和字符串两种编码, 以及 1e9 这样值为整数的浮点数
+func unmarshal(data []byte) (int64, error) {
+	text, err := number(data)
+	if err != nil || text == "" {
+		return 0, err
+	}
+
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err == nil {
+		return value, nil
+	}
+
+	float, ferr := strconv.ParseFloat(text, 64)
+	if ferr != nil || float != math.Trunc(float) || math.Abs(float) >= 1<<63 {
+		return 0, err
+	}
+
+	return int64(float), nil
+}
+
+func (id UserID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+func (id *UserID) UnmarshalJSON(data []byte) error {
+	value, err := unmarshal(data)
+	*id = UserID(value)
+	return err
+}
+
+func (id GroupID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+func (id *GroupID) UnmarshalJSON(data []byte) error {
+	value, err := unmarshal(data)
+	*id = GroupID(value)
+	return err
+}
+
+func (id MessageID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+func (id *MessageID) UnmarshalJSON(data []byte) error {
+	value, err := unmarshal(data)
+	*id = MessageID(value)
+	return err
+}
+
+// 解析QQ号
+func ParseUserID(text string) (UserID, error) {
+	value, err := strconv.ParseInt(text, 10, 64)
+	return UserID(value), err
+}
+
+// 解析群号
+func ParseGroupID(text string) (GroupID, error) {
+	value, err := strconv.ParseInt(text, 10, 64)
+	return GroupID(value), err
+}
+
+// 解析消息ID
+func ParseMessageID(text string) (MessageID, error) {
+	value, err := strconv.ParseInt(text, 10, 64)
+	return MessageID(value), err
+}
@@ -0,0 +1,47 @@
+package qq
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnmarshalID(t *testing.T) {
+	tests := []struct {
+		data string
+		want UserID
+		ok   bool
+	}{
+		{`10001`, 10001, true},
+		{`"10001"`, 10001, true},
+		{`" 10001 "`, 10001, true},
+		{`1e4`, 10000, true},
+		{`"1.0001e4"`, 10001, true},
+		{`null`, 0, true},
+		{`""`, 0, true},
+		{`9007199254740993`, 9007199254740993, true},
+		{`1.5`, 0, false},
+		{`"abc"`, 0, false},
+		{`true`, 0, false},
+	}
+
+	for _, test := range tests {
+		var id UserID
+
+		err := json.Unmarshal([]byte(test.data), &id)
+		if (err == nil) != test.ok || (test.ok && id != test.want) {
+			t.Errorf("Unmarshal(%s) = %d, %v, want %d, ok %v", test.data, id, err, test.want, test.ok)
+		}
+	}
+}
+
+func TestUnmarshalIDFields(t *testing.T) {
+	var v struct {
+		GroupID   GroupID   `json:"group_id"`
+		MessageID MessageID `json:"message_id"`
+	}
+
+	err := json.Unmarshal([]byte(`{"group_id":"123","message_id":-45}`), &v)
+	if err != nil || v.GroupID != 123 || v.MessageID != -45 {
+		t.Errorf("Unmarshal = %+v, %v", v, err)
+	}
+}
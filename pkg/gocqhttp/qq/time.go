@@ -0,0 +1,82 @@
+package qq
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// 时间
+//
+// * 以Unix时间戳(秒)编码, 0表示零值, 解码时兼容字符串和浮点数
+type Time struct {
+	time.Time
+}
+
+// 时长
+//
+// * 以秒编码
+type Duration struct {
+	time.Duration
+}
+
+// 解码秒数
+//
+// * 兼容数字和字符串两种编码, 以及带小数和指数的浮点数, 如 1.7e9
+func seconds(data []byte) (time.Duration, error) {
+	text, err := number(data)
+	if err != nil || text == "" {
+		return 0, err
+	}
+
+	const limit = math.MaxInt64 / int64(time.Second)
+
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err == nil {
+		if value > limit || value < -limit {
+			return 0, &strconv.NumError{Func: "ParseInt", Num: text, Err: strconv.ErrRange}
+		}
+
+		return time.Duration(value) * time.Second, nil
+	}
+
+	float, ferr := strconv.ParseFloat(text, 64)
+	if ferr != nil || math.IsNaN(float) || math.Abs(float) > float64(limit) {
+		return 0, err
+	}
+
+	return time.Duration(math.Round(float * float64(time.Second))), nil
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("0"), nil
+	}
+
+	return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	value, err := seconds(data)
+	if err != nil {
+		return err
+	}
+
+	if value == 0 {
+		t.Time = time.Time{}
+	} else {
+		t.Time = time.Unix(0, 0).Add(value)
+	}
+
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(d.Duration/time.Second), 10)), nil
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	value, err := seconds(data)
+	d.Duration = value
+	return err
+}
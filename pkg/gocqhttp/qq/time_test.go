@@ -0,0 +1,71 @@
+package qq
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestUnmarshalTime(t *testing.T) {
+	tests := []struct {
+		data string
+		want time.Time
+		ok   bool
+	}{
+		{`1700000000`, time.Unix(1700000000, 0), true},
+		{`"1700000000"`, time.Unix(1700000000, 0), true},
+		{`1.7e9`, time.Unix(1700000000, 0), true},
+		{`"1.7e9"`, time.Unix(1700000000, 0), true},
+		{`1700000000.5`, time.Unix(1700000000, 5e8), true},
+		{`0`, time.Time{}, true},
+		{`null`, time.Time{}, true},
+		{`"soon"`, time.Time{}, false},
+		{`1e300`, time.Time{}, false},
+		{`99999999999999`, time.Time{}, false},
+	}
+
+	for _, test := range tests {
+		var v Time
+
+		err := json.Unmarshal([]byte(test.data), &v)
+		if (err == nil) != test.ok || (test.ok && !v.Equal(test.want)) {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v, ok %v", test.data, v.Time, err, test.want, test.ok)
+		}
+	}
+}
+
+func TestTimeRoundTrip(t *testing.T) {
+	for _, want := range []Time{{}, {time.Unix(1700000000, 0)}} {
+		data, err := json.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got Time
+
+		if err := json.Unmarshal(data, &got); err != nil || !got.Equal(want.Time) {
+			t.Errorf("round trip of %v = %v, %v", want.Time, got.Time, err)
+		}
+	}
+}
+
+func TestUnmarshalDuration(t *testing.T) {
+	tests := []struct {
+		data string
+		want time.Duration
+	}{
+		{`60`, time.Minute},
+		{`"60"`, time.Minute},
+		{`1.5`, 1500 * time.Millisecond},
+		{`3.6e3`, time.Hour},
+		{`null`, 0},
+	}
+
+	for _, test := range tests {
+		var d Duration
+
+		if err := json.Unmarshal([]byte(test.data), &d); err != nil || d.Duration != test.want {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", test.data, d.Duration, err, test.want)
+		}
+	}
+}
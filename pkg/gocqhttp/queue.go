@@ -0,0 +1,425 @@
+package gocqhttp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"koi/pkg/gocqhttp/qq"
+
+	"github.com/gorilla/websocket"
+)
+
+// 优先级
+const (
+	PriorityHigh   = iota // 管理操作
+	PriorityNormal        // 普通请求
+	PriorityLow           // 批量发送等可延后的请求
+
+	priorities
+)
+
+// 各API终结点默认的优先级
+//
+// * 可以通过 Options.Priorities 覆盖, 均未设置的终结点为 PriorityNormal
+var default_priorities = map[string]int{
+	"set_group_kick":          PriorityHigh,
+	"set_group_ban":           PriorityHigh,
+	"set_group_anonymous_ban": PriorityHigh,
+	"set_group_whole_ban":     PriorityHigh,
+	"delete_msg":              PriorityHigh,
+	"set_group_add_request":   PriorityHigh,
+}
+
+type priorityKey struct{}
+
+// 指定单次调用的优先级
+//
+// * 优先于连接设置中的 Options.Priorities
+//
+// * 用于 Call, CallAs 和带 Context 后缀的函数
+func WithPriority(ctx context.Context, priority int) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+func priorityOf(ctx context.Context, action string, priorities map[string]int) int {
+	priority, ok := ctx.Value(priorityKey{}).(int)
+	if !ok {
+		priority, ok = priorities[action]
+	}
+	if !ok {
+		priority, ok = default_priorities[action]
+	}
+	if !ok {
+		priority = PriorityNormal
+	}
+
+	if priority < PriorityHigh {
+		return PriorityHigh
+	}
+
+	if priority > PriorityLow {
+		return PriorityLow
+	}
+
+	return priority
+}
+
+// 速率
+type Rate struct {
+	Every time.Duration // 每产生一个令牌的间隔, 0为不限制
+	Burst int           // 令牌桶容量, 即允许连续发送的条数
+}
+
+// 发送消息的限速策略
+//
+// * 零值不限速, 只作用于发送消息的API终结点, 管理操作等不受限制
+type LimitPolicy struct {
+	Global Rate // 所有消息
+	Group  Rate // 每个群
+	User   Rate // 每个私聊用户
+}
+
+// 受限速的API终结点
+var limited = map[string]bool{
+	"send_msg":                 true,
+	"send_private_msg":         true,
+	"send_group_msg":           true,
+	"send_forward_msg":         true,
+	"send_private_forward_msg": true,
+	"send_group_forward_msg":   true,
+	"send_guild_channel_msg":   true,
+}
+
+// 令牌桶
+type bucket struct {
+	tat time.Time // 下一个令牌的理论到达时间
+}
+
+// 预定一个令牌, 返回需要等待的时间
+func (b *bucket) reserve(now time.Time, rate Rate) time.Duration {
+	if rate.Every <= 0 {
+		return 0
+	}
+
+	burst := rate.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	if b.tat.Before(now) {
+		b.tat = now
+	}
+
+	b.tat = b.tat.Add(rate.Every)
+
+	wait := b.tat.Add(-time.Duration(burst) * rate.Every).Sub(now)
+	if wait < 0 {
+		return 0
+	}
+
+	return wait
+}
+
+// 已预定的令牌
+type reservation struct {
+	bucket *bucket
+	rate   Rate
+}
+
+// 退还令牌
+//
+// * 用于预定后取消的调用, 之后的预定不必再为其等待
+func (r reservation) cancel() {
+	r.bucket.tat = r.bucket.tat.Add(-r.rate.Every)
+}
+
+// 等待中的请求
+type waiter struct {
+	ready    chan struct{}
+	canceled bool
+}
+
+// 连接状态
+type conn struct {
+	mu    sync.Mutex
+	busy  bool                  // 是否有请求正在收发
+	lanes [priorities][]*waiter // 各优先级的等待队列, 同一优先级先进先出
+
+	global bucket
+	groups map[qq.GroupID]*bucket
+	users  map[qq.UserID]*bucket
+
+	limiting [priorities]int // 等待限速的请求数
+	calls    uint64
+	waited   time.Duration
+	max_wait time.Duration
+
+	options Options         // 连接设置
+	live    *websocket.Conn // 重连后使用的连接, 为nil时使用调用方持有的连接
+	broken  error           // 读写失败的原因, 不为nil时需要重连
+}
+
+// 各连接的状态, *websocket.Conn -> *conn
+var conns sync.Map
+
+func getConn(ws *websocket.Conn) *conn {
+	if c, ok := conns.Load(ws); ok {
+		return c.(*conn)
+	}
+
+	c, _ := conns.LoadOrStore(ws, &conn{
+		groups: make(map[qq.GroupID]*bucket),
+		users:  make(map[qq.UserID]*bucket),
+	})
+
+	return c.(*conn)
+}
+
+// 消息目标
+type target struct {
+	UserID  qq.UserID  `json:"user_id"`
+	GroupID qq.GroupID `json:"group_id"`
+}
+
+// 等待限速和发送队列
+//
+// * 返回时已取得连接, 收发完成后需调用 release
+//
+// * ctx 取消时退还已预定的令牌
+func (c *conn) wait(ctx context.Context, message ws_data) error {
+	start := time.Now()
+	options := c.config()
+	priority := priorityOf(ctx, message.Action, options.Priorities)
+
+	var reserved []reservation
+
+	if limited[message.Action] {
+		c.mu.Lock()
+		var delay time.Duration
+		delay, reserved = c.reserve(start, message.Params, options.Limit)
+		c.limiting[priority]++
+		c.mu.Unlock()
+
+		err := sleep(ctx, delay)
+
+		c.mu.Lock()
+		c.limiting[priority]--
+		c.mu.Unlock()
+
+		if err != nil {
+			c.refund(reserved)
+			return err
+		}
+	}
+
+	err := c.acquire(ctx, priority)
+	if err != nil {
+		c.refund(reserved)
+		return err
+	}
+
+	wait := time.Since(start)
+
+	c.mu.Lock()
+	c.calls++
+	c.waited += wait
+	if wait > c.max_wait {
+		c.max_wait = wait
+	}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// 预定全局和会话的令牌
+//
+// * 返回需要等待的时间和已预定的令牌
+func (c *conn) reserve(now time.Time, params any, limit LimitPolicy) (delay time.Duration, reserved []reservation) {
+	take := func(b *bucket, rate Rate) time.Duration {
+		if rate.Every > 0 {
+			reserved = append(reserved, reservation{b, rate})
+		}
+
+		return b.reserve(now, rate)
+	}
+
+	delay = take(&c.global, limit.Global)
+
+	var to target
+
+	if data, err := json.Marshal(params); err == nil {
+		json.Unmarshal(data, &to)
+	}
+
+	var wait time.Duration
+
+	switch {
+	case to.GroupID != 0:
+		if c.groups[to.GroupID] == nil {
+			c.prune(now)
+			c.groups[to.GroupID] = &bucket{}
+		}
+
+		wait = take(c.groups[to.GroupID], limit.Group)
+	case to.UserID != 0:
+		if c.users[to.UserID] == nil {
+			c.prune(now)
+			c.users[to.UserID] = &bucket{}
+		}
+
+		wait = take(c.users[to.UserID], limit.User)
+	}
+
+	if wait > delay {
+		delay = wait
+	}
+
+	return delay, reserved
+}
+
+// 退还令牌
+func (c *conn) refund(reserved []reservation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, r := range reserved {
+		r.cancel()
+	}
+}
+
+// 清除已经回满的令牌桶
+func (c *conn) prune(now time.Time) {
+	if len(c.groups)+len(c.users) < 1024 {
+		return
+	}
+
+	for id, b := range c.groups {
+		if b.tat.Before(now) {
+			delete(c.groups, id)
+		}
+	}
+
+	for id, b := range c.users {
+		if b.tat.Before(now) {
+			delete(c.users, id)
+		}
+	}
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 取得连接
+func (c *conn) acquire(ctx context.Context, priority int) error {
+	c.mu.Lock()
+
+	if !c.busy {
+		c.busy = true
+		c.mu.Unlock()
+		return nil
+	}
+
+	w := &waiter{ready: make(chan struct{})}
+	c.lanes[priority] = append(c.lanes[priority], w)
+	c.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		select {
+		case <-w.ready:
+			// 取消的同时已取得连接, 转交给下一个请求
+			c.next()
+		default:
+			w.canceled = true
+		}
+
+		return ctx.Err()
+	}
+}
+
+// 释放连接
+func (c *conn) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.next()
+}
+
+// 将连接交给优先级最高的等待者
+func (c *conn) next() {
+	for priority := range c.lanes {
+		for len(c.lanes[priority]) > 0 {
+			w := c.lanes[priority][0]
+			c.lanes[priority][0] = nil
+			c.lanes[priority] = c.lanes[priority][1:]
+
+			if !w.canceled {
+				close(w.ready)
+				return
+			}
+		}
+	}
+
+	c.busy = false
+}
+
+// 发送队列统计
+type QueueStats struct {
+	Depth   [priorities]int // 各优先级排队中的请求数, 包括等待限速的消息
+	Calls   uint64          // 已出队的请求数
+	Waited  time.Duration   // 累计等待时间
+	MaxWait time.Duration   // 最长等待时间
+}
+
+// 平均等待时间
+func (stats QueueStats) AverageWait() time.Duration {
+	if stats.Calls == 0 {
+		return 0
+	}
+
+	return stats.Waited / time.Duration(stats.Calls)
+}
+
+// 获取连接的发送队列统计
+func GetQueueStats(ws *websocket.Conn) (stats QueueStats) {
+	c := getConn(ws)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for priority := range c.lanes {
+		stats.Depth[priority] = c.limiting[priority]
+
+		for _, w := range c.lanes[priority] {
+			if !w.canceled {
+				stats.Depth[priority]++
+			}
+		}
+	}
+
+	stats.Calls = c.calls
+	stats.Waited = c.waited
+	stats.MaxWait = c.max_wait
+
+	return stats
+}
@@ -0,0 +1,174 @@
+package gocqhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	now := time.Now()
+	rate := Rate{Every: time.Second, Burst: 2}
+
+	var b bucket
+
+	for i, want := range []time.Duration{0, 0, time.Second, 2 * time.Second} {
+		if got := b.reserve(now, rate); got != want {
+			t.Errorf("reserve #%d = %s, want %s", i, got, want)
+		}
+	}
+
+	// 令牌按速率恢复
+	for i, want := range []time.Duration{0, time.Second} {
+		if got := b.reserve(now.Add(3*time.Second), rate); got != want {
+			t.Errorf("reserve #%d after 3s = %s, want %s", i, got, want)
+		}
+	}
+
+	// 不限速
+	if got := b.reserve(now, Rate{}); got != 0 {
+		t.Errorf("reserve without rate = %s, want 0", got)
+	}
+}
+
+func TestLimitRefund(t *testing.T) {
+	_, ws := newFakeServer(t, nil)
+
+	Configure(ws, Options{Limit: LimitPolicy{Group: Rate{Every: time.Hour, Burst: 1}}})
+
+	if _, err := SendGroupText(ws, 1, "first"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 等待令牌时被取消
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := SendGroupTextContext(ctx, ws, 1, "second"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	// 取消的调用退还令牌, 下一次只需等待第一次之后的间隔
+	c := getConn(ws)
+
+	c.mu.Lock()
+	delay, _ := c.reserve(time.Now(), target{GroupID: 1}, c.options.Limit)
+	c.mu.Unlock()
+
+	if delay > time.Hour {
+		t.Errorf("delay = %s, want at most 1h", delay)
+	}
+
+	// 其他群不受影响
+	if _, err := SendGroupText(ws, 2, "other"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPriorityLanes(t *testing.T) {
+	hold := make(chan struct{})
+
+	server, ws := newFakeServer(t, func(action string, _ json.RawMessage) map[string]any {
+		if action == "get_status" {
+			<-hold
+		}
+
+		return ok(nil)
+	})
+
+	go GetStatus(ws)
+
+	// 等待第一个调用占用连接
+	for len(server.Actions()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	queued := func(n int) {
+		for {
+			stats := GetQueueStats(ws)
+			if stats.Depth[PriorityHigh]+stats.Depth[PriorityNormal]+stats.Depth[PriorityLow] == n {
+				return
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	done := make(chan struct{}, 3)
+	call := func(ctx context.Context, action string) {
+		Call(ctx, ws, action, nil, nil)
+		done <- struct{}{}
+	}
+
+	go call(WithPriority(context.Background(), PriorityLow), "low")
+	queued(1)
+	go call(context.Background(), "normal")
+	queued(2)
+	go call(context.Background(), "set_group_kick")
+	queued(3)
+
+	close(hold)
+
+	for i := 0; i < 3; i++ {
+		<-done
+	}
+
+	want := []string{"get_status", "set_group_kick", "normal", "low"}
+
+	if got := server.Actions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("actions = %v, want %v", got, want)
+	}
+}
+
+func TestLateReply(t *testing.T) {
+	_, ws := newFakeServer(t, func(action string, _ json.RawMessage) map[string]any {
+		switch action {
+		case "slow":
+			time.Sleep(50 * time.Millisecond)
+		case "lost":
+			return nil
+		}
+
+		return ok(action)
+	})
+
+	Configure(ws, Options{LateReply: 200 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := Call(ctx, ws, "slow", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	// 迟到的响应被跳过, 之后的调用收到自己的响应
+	got, err := CallAs[string](context.Background(), ws, "next", nil)
+	if err != nil || got != "next" {
+		t.Fatalf("next = %q, %v", got, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := Call(ctx, ws, "lost", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	// 丢失的响应不会一直占用连接, 超时后连接被关闭
+	start := time.Now()
+
+	if err := Call(context.Background(), ws, "after", nil, nil); err == nil {
+		t.Error("call after lost reply succeeded on a closed connection")
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call after lost reply blocked for %s", elapsed)
+	}
+
+	if _, ok := conns.Load(ws); ok {
+		t.Error("state of the closed connection was not cleared")
+	}
+}
@@ -0,0 +1,116 @@
+package gocqhttp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// 重试策略
+type RetryPolicy struct {
+	Attempts   int           // 最多尝试次数, 包括首次调用, 小于2为不重试
+	Backoff    time.Duration // 首次重试前的等待时间, 之后每次翻倍
+	MaxBackoff time.Duration // 等待时间上限, 0为不限制
+	// 是否可以安全地重复调用
+	//
+	// * 为false时读写失败只在请求确定未发出时重试, 避免重复发送消息
+	//
+	// * 获取类的API终结点总是视为可以重复调用
+	Idempotent bool
+}
+
+// 默认重试策略, 默认不重试
+//
+// * 如 Retry = RetryPolicy{Attempts: 3, Backoff: 500 * time.Millisecond}
+var Retry = RetryPolicy{}
+
+// 各API终结点的重试策略, 优先于 Retry
+//
+// * 应在发起调用前设置, 运行中修改不是并发安全的
+var Retries = map[string]RetryPolicy{}
+
+// 请求未发出的错误
+type notSentError struct {
+	err error
+}
+
+func (err *notSentError) Error() string {
+	return err.err.Error()
+}
+
+func (err *notSentError) Unwrap() error {
+	return err.err
+}
+
+func retryPolicy(action string) RetryPolicy {
+	if policy, ok := Retries[action]; ok {
+		return policy
+	}
+
+	return Retry
+}
+
+// 是否为获取类的API终结点
+func idempotent(action string) bool {
+	for _, prefix := range []string{"get_", "_get_", ".get_", "can_", "qidian_get_"} {
+		if strings.HasPrefix(action, prefix) {
+			return true
+		}
+	}
+
+	switch action {
+	case "check_url_safely", "ocr_image":
+		return true
+	}
+
+	return false
+}
+
+// 是否可以重试
+//
+// * go-cqhttp 返回的错误只在 Error.Temporary 时重试, ctx 的取消不会重试
+//
+// * 读写失败的连接不再可用, 只在可以重连时重试
+func (policy RetryPolicy) retryable(action string, err error, reconnect bool) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var api *Error
+
+	if errors.As(err, &api) {
+		return api.Temporary()
+	}
+
+	if !reconnect {
+		return false
+	}
+
+	var not_sent *notSentError
+
+	if errors.As(err, &not_sent) {
+		return true
+	}
+
+	return policy.Idempotent || idempotent(action)
+}
+
+// 第 attempt 次调用失败后的等待时间
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := policy.Backoff
+
+	for i := 1; i < attempt && backoff > 0; i++ {
+		backoff *= 2
+
+		if policy.MaxBackoff > 0 && backoff >= policy.MaxBackoff {
+			break
+		}
+	}
+
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		return policy.MaxBackoff
+	}
+
+	return backoff
+}
@@ -0,0 +1,106 @@
+package gocqhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 设置测试期间的重试策略
+func withRetry(t *testing.T, policy RetryPolicy) {
+	saved := Retry
+	Retry = policy
+	t.Cleanup(func() { Retry = saved })
+}
+
+func TestRetryTemporaryError(t *testing.T) {
+	withRetry(t, RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+	sends := 0
+
+	server, ws := newFakeServer(t, func(action string, _ json.RawMessage) map[string]any {
+		sends++
+		if sends == 1 {
+			return failed("SEND_MSG_API_ERROR", "请参考 go-cqhttp 端输出")
+		}
+
+		return ok(map[string]any{"message_id": 42})
+	})
+
+	message_id, err := SendGroupText(ws, 1, "hello")
+	if err != nil || message_id != 42 {
+		t.Fatalf("SendGroupText = %v, %v, want 42, nil", message_id, err)
+	}
+
+	if got := server.Actions(); !reflect.DeepEqual(got, []string{"send_msg", "send_msg"}) {
+		t.Errorf("actions = %v", got)
+	}
+}
+
+func TestRetryPermanentError(t *testing.T) {
+	withRetry(t, RetryPolicy{Attempts: 3})
+
+	server, ws := newFakeServer(t, func(string, json.RawMessage) map[string]any {
+		return failed("GROUP_NOT_FOUND", "群聊不存在")
+	})
+
+	var api *Error
+
+	if _, err := GetGroupInfo(ws, 1); !errors.As(err, &api) || api.Temporary() {
+		t.Fatalf("err = %v, want permanent *Error", err)
+	}
+
+	if got := len(server.Actions()); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryReconnect(t *testing.T) {
+	withRetry(t, RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+	calls := 0
+
+	server, ws := newFakeServer(t, func(string, json.RawMessage) map[string]any {
+		calls++
+		if calls == 1 {
+			return drop()
+		}
+
+		return ok(map[string]any{"group_id": 1, "group_name": "koi"})
+	})
+
+	Configure(ws, Options{Dial: server.Dial})
+
+	info, err := GetGroupInfo(ws, 1)
+	if err != nil || info.GroupName != "koi" {
+		t.Fatalf("GetGroupInfo = %+v, %v", info, err)
+	}
+
+	// 之后的调用使用新连接
+	if _, err := GetGroupInfo(ws, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(server.Actions()); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestRetryWithoutReconnect(t *testing.T) {
+	withRetry(t, RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+	server, ws := newFakeServer(t, func(string, json.RawMessage) map[string]any {
+		return drop()
+	})
+
+	if _, err := GetGroupInfo(ws, 1); err == nil {
+		t.Fatal("GetGroupInfo succeeded on a dropped connection")
+	}
+
+	// 同一个连接上的读取错误会一直存在, 不重连时不会重试
+	if got := len(server.Actions()); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
@@ -0,0 +1,290 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"koi/pkg/log"
+)
+
+// 严格模式
+//
+// * 启用后每次解码都会检查数据与结构体的差异, 并将结果报告给 Hook
+var Strict bool
+
+// 差异类型
+const (
+	Unknown  = "unknown"  // 数据中存在结构体未定义的字段
+	Missing  = "missing"  // 结构体定义的字段在数据中不存在
+	Mismatch = "mismatch" // 字段类型不匹配
+)
+
+// 协议差异
+type Drift struct {
+	Type   string // 结构体类型, 如 event.GroupMessage
+	Path   string // 字段路径, 如 sender.card, 数组元素为 []
+	Kind   string // 差异类型
+	Detail string // 说明
+}
+
+func (drift Drift) String() string {
+	return fmt.Sprintf("%s %s: %s %s", drift.Type, drift.Path, drift.Kind, drift.Detail)
+}
+
+// 诊断回调
+//
+// * 为nil时输出警告日志
+var Hook func(drift Drift)
+
+// 解码
+//
+// * 行为与 json.Unmarshal 相同, 严格模式下额外报告差异
+func Unmarshal(data []byte, v any) error {
+	err := json.Unmarshal(data, v)
+
+	if Strict {
+		for _, drift := range Check(data, v) {
+			if Hook != nil {
+				Hook(drift)
+			} else {
+				log.Warn(drift)
+			}
+		}
+	}
+
+	return err
+}
+
+var unmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// 检查差异
+//
+// * 实现了 json.Unmarshaler 的类型只检查能否解码, 不检查其内部字段
+func Check(data []byte, v any) (drifts []Drift) {
+	var value any
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&value)
+	if err != nil {
+		return []Drift{{Type: typeName(reflect.TypeOf(v)), Kind: Mismatch, Detail: err.Error()}}
+	}
+
+	t := reflect.TypeOf(v)
+	name := typeName(t)
+	seen := make(map[string]bool)
+
+	walk(value, t, "", func(kind, path, detail string) {
+		if seen[kind+path] {
+			return
+		}
+
+		seen[kind+path] = true
+		drifts = append(drifts, Drift{name, path, kind, detail})
+	})
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Path != drifts[j].Path {
+			return drifts[i].Path < drifts[j].Path
+		}
+
+		return drifts[i].Kind < drifts[j].Kind
+	})
+
+	return drifts
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "nil"
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.String()
+}
+
+// 字段信息
+type field struct {
+	t         reflect.Type
+	omitempty bool
+}
+
+// 获取结构体的JSON字段
+func fields(t reflect.Type) map[string]field {
+	result := make(map[string]field)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		// 匿名嵌入的结构体字段提升到上层
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				for k, v := range fields(embedded) {
+					if _, exists := result[k]; !exists {
+						result[k] = v
+					}
+				}
+
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		result[name] = field{f.Type, strings.Contains(options, "omitempty")}
+	}
+
+	return result
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// 检查值与类型是否匹配
+func walk(value any, t reflect.Type, path string, report func(kind, path, detail string)) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if value == nil {
+		return
+	}
+
+	// 自定义解码的类型无法按结构检查, 只检查能否解码
+	if t.Implements(unmarshaler) || reflect.PointerTo(t).Implements(unmarshaler) {
+		data, err := json.Marshal(value)
+		if err == nil {
+			err = json.Unmarshal(data, reflect.New(t).Interface())
+		}
+
+		if err != nil {
+			report(Mismatch, path, err.Error())
+		}
+
+		return
+	}
+
+	mismatch := func(want string) {
+		report(Mismatch, path, fmt.Sprintf("want %s, got %s", want, kindOf(value)))
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			mismatch("object")
+			return
+		}
+
+		fields := fields(t)
+
+		for name, v := range object {
+			f, ok := fields[name]
+			if !ok {
+				report(Unknown, join(path, name), kindOf(v))
+				continue
+			}
+
+			walk(v, f.t, join(path, name), report)
+		}
+
+		for name, f := range fields {
+			if _, ok := object[name]; !ok && !f.omitempty {
+				report(Missing, join(path, name), f.t.String())
+			}
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			mismatch("object")
+			return
+		}
+
+		for _, v := range object {
+			walk(v, t.Elem(), join(path, "*"), report)
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			if _, ok := value.(string); !ok {
+				mismatch("string")
+			}
+
+			return
+		}
+
+		array, ok := value.([]any)
+		if !ok {
+			mismatch("array")
+			return
+		}
+
+		for _, v := range array {
+			walk(v, t.Elem(), path+"[]", report)
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			mismatch("string")
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			mismatch("bool")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if _, ok := value.(json.Number); !ok {
+			mismatch("number")
+		}
+	}
+}
+
+// JSON值类型
+func kindOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case json.Number:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
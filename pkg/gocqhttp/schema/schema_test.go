@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// 自定义解码的类型, 只接受数字
+type stamp int64
+
+func (s *stamp) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return errors.New("stamp: not an integer")
+	}
+
+	*s = stamp(value)
+	return nil
+}
+
+type base struct {
+	Time stamp `json:"time"`
+}
+
+type sender struct {
+	UserID int64  `json:"user_id"`
+	Card   string `json:"card,omitempty"`
+}
+
+type message struct {
+	base
+	Text    string         `json:"text"`
+	Sender  sender         `json:"sender"`
+	Tags    []sender       `json:"tags"`
+	Extra   map[string]int `json:"extra,omitempty"`
+	Ignored string         `json:"-"`
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Drift
+	}{
+		{
+			name: "match",
+			data: `{"time":1,"text":"a","sender":{"user_id":1},"tags":[]}`,
+		},
+		{
+			name: "unknown",
+			data: `{"time":1,"text":"a","sender":{"user_id":1,"role":"owner"},"tags":null,"font":0}`,
+			want: []Drift{
+				{"schema.message", "font", Unknown, "number"},
+				{"schema.message", "sender.role", Unknown, "string"},
+			},
+		},
+		{
+			name: "missing",
+			data: `{"time":1,"sender":{},"tags":[]}`,
+			want: []Drift{
+				{"schema.message", "sender.user_id", Missing, "int64"},
+				{"schema.message", "text", Missing, "string"},
+			},
+		},
+		{
+			name: "mismatch",
+			data: `{"time":1,"text":1,"sender":{"user_id":"1"},"tags":[{"user_id":true},{"user_id":false}],"extra":{"a":"x"}}`,
+			want: []Drift{
+				{"schema.message", "extra.*", Mismatch, "want number, got string"},
+				{"schema.message", "sender.user_id", Mismatch, "want number, got string"},
+				{"schema.message", "tags[].user_id", Mismatch, "want number, got bool"},
+				{"schema.message", "text", Mismatch, "want string, got number"},
+			},
+		},
+		{
+			// 自定义解码的字段按能否解码检查
+			name: "unmarshaler",
+			data: `{"time":"soon","text":"a","sender":{"user_id":1},"tags":[]}`,
+			want: []Drift{
+				{"schema.message", "time", Mismatch, "stamp: not an integer"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := Check([]byte(test.data), &message{})
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Check = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCheckInvalid(t *testing.T) {
+	drifts := Check([]byte(`{`), &message{})
+
+	if len(drifts) != 1 || drifts[0].Kind != Mismatch || drifts[0].Path != "" {
+		t.Errorf("Check = %v, want one mismatch at the root", drifts)
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	var reported []Drift
+
+	savedStrict, savedHook := Strict, Hook
+	Hook = func(drift Drift) { reported = append(reported, drift) }
+	t.Cleanup(func() { Strict, Hook = savedStrict, savedHook })
+
+	data := []byte(`{"time":1,"text":"a","sender":{"user_id":1},"tags":[],"font":0}`)
+
+	Strict = false
+
+	var v message
+	if err := Unmarshal(data, &v); err != nil || v.Text != "a" {
+		t.Fatalf("Unmarshal = %+v, %v", v, err)
+	}
+
+	if len(reported) != 0 {
+		t.Errorf("reported %v outside strict mode", reported)
+	}
+
+	Strict = true
+
+	if err := Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []Drift{{"schema.message", "font", Unknown, "number"}}; !reflect.DeepEqual(reported, want) {
+		t.Errorf("reported %v, want %v", reported, want)
+	}
+
+	// 解码错误仍然返回, 同时报告差异
+	reported = nil
+
+	var mismatch *json.UnmarshalTypeError
+	if err := Unmarshal([]byte(`{"time":1,"text":1,"sender":{"user_id":1},"tags":[]}`), &v); !errors.As(err, &mismatch) {
+		t.Errorf("err = %v, want *json.UnmarshalTypeError", err)
+	}
+
+	if len(reported) != 1 || reported[0].Path != "text" {
+		t.Errorf("reported %v, want mismatch at text", reported)
+	}
+}
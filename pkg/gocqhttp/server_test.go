@@ -0,0 +1,128 @@
+package gocqhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// 模拟的 go-cqhttp
+type fakeServer struct {
+	URL string
+
+	mu      sync.Mutex
+	actions []string
+
+	serving sync.Mutex // 多个连接的响应依次生成
+	reply   func(action string, params json.RawMessage) map[string]any
+}
+
+// 成功的响应
+func ok(data any) map[string]any {
+	return map[string]any{"status": "ok", "retcode": 0, "data": data}
+}
+
+// 失败的响应
+func failed(code, wording string) map[string]any {
+	return map[string]any{"status": "failed", "retcode": 100, "msg": code, "wording": wording}
+}
+
+// 断开连接的响应
+func drop() map[string]any {
+	return map[string]any{"drop": true}
+}
+
+// 启动模拟的 go-cqhttp 并连接
+//
+// * reply 为nil时所有调用返回空数据, 返回nil时不响应, 返回 drop() 时断开连接
+func newFakeServer(t *testing.T, reply func(action string, params json.RawMessage) map[string]any) (*fakeServer, *websocket.Conn) {
+	t.Helper()
+
+	server := &fakeServer{reply: reply}
+
+	var upgrader websocket.Upgrader
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		for {
+			var request struct {
+				Action string          `json:"action"`
+				Params json.RawMessage `json:"params"`
+				Echo   json.RawMessage `json:"echo"`
+			}
+
+			if ws.ReadJSON(&request) != nil {
+				return
+			}
+
+			server.mu.Lock()
+			server.actions = append(server.actions, request.Action)
+			server.mu.Unlock()
+
+			response := ok(nil)
+			if server.reply != nil {
+				server.serving.Lock()
+				response = server.reply(request.Action, request.Params)
+				server.serving.Unlock()
+			}
+
+			if response == nil {
+				continue
+			}
+
+			if response["drop"] == true {
+				return
+			}
+
+			response["echo"] = request.Echo
+
+			if ws.WriteJSON(response) != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(httpServer.Close)
+
+	server.URL = "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	ws := server.dial(t)
+	t.Cleanup(func() { Close(ws) })
+
+	return server, ws
+}
+
+// 建立新连接
+func (server *fakeServer) dial(t *testing.T) *websocket.Conn {
+	t.Helper()
+
+	ws, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ws
+}
+
+// 建立新连接, 用于 Options.Dial
+func (server *fakeServer) Dial() (*websocket.Conn, error) {
+	ws, _, err := websocket.DefaultDialer.Dial(server.URL, nil)
+	return ws, err
+}
+
+// 已收到的调用
+func (server *fakeServer) Actions() []string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return append([]string(nil), server.actions...)
+}
@@ -0,0 +1,93 @@
+package gocqhttp
+
+import (
+	"koi/pkg/gocqhttp/cqcode"
+	"koi/pkg/gocqhttp/qq"
+
+	"github.com/gorilla/websocket"
+)
+
+// 长消息选项
+type LongMessageOption struct {
+	cqcode.SplitOption
+
+	// 分割后的条数超过该值时改为发送合并转发消息, 0为不使用合并转发
+	Forward int
+	// 合并转发消息中显示的发送者, 为空时使用登录号信息
+	ForwardName string
+	ForwardUin  qq.UserID
+}
+
+// 发送私聊长消息
+//
+// * 按 option 分割后逐条发送, 返回所有消息ID
+func SendPrivateLongMessage(ws *websocket.Conn, user_id qq.UserID, text string, option LongMessageOption) (message_ids []qq.MessageID, err error) {
+	return sendLongMessage(ws, user_id, 0, text, option)
+}
+
+// 发送群长消息
+//
+// * 按 option 分割后逐条发送, 返回所有消息ID
+func SendGroupLongMessage(ws *websocket.Conn, group_id qq.GroupID, text string, option LongMessageOption) (message_ids []qq.MessageID, err error) {
+	return sendLongMessage(ws, 0, group_id, text, option)
+}
+
+func sendLongMessage(ws *websocket.Conn, user_id qq.UserID, group_id qq.GroupID, text string, option LongMessageOption) (message_ids []qq.MessageID, err error) {
+	parts := cqcode.Split(text, option.SplitOption)
+
+	if option.Forward > 0 && len(parts) > option.Forward {
+		id, err := sendLongForward(ws, user_id, group_id, parts, option)
+		if err != nil {
+			return nil, err
+		}
+
+		return []qq.MessageID{id}, nil
+	}
+
+	for _, part := range parts {
+		id, err := SendTemporaryMessage(ws, user_id, group_id, part)
+		if err != nil {
+			return message_ids, err
+		}
+
+		message_ids = append(message_ids, id)
+	}
+
+	return message_ids, nil
+}
+
+// 以合并转发消息发送分割后的长消息
+func sendLongForward(ws *websocket.Conn, user_id qq.UserID, group_id qq.GroupID, parts []string, option LongMessageOption) (message_id qq.MessageID, err error) {
+	name, uin := option.ForwardName, option.ForwardUin
+
+	if name == "" || uin == 0 {
+		login, err := GetLoginInfo(ws)
+		if err != nil {
+			return 0, err
+		}
+
+		if name == "" {
+			name = login.Nickname
+		}
+
+		if uin == 0 {
+			uin = login.UserID
+		}
+	}
+
+	var messages []ForwardMessage
+
+	for _, part := range parts {
+		messages = append(messages, ForwardMessage{
+			Name:    name,
+			Uin:     uin,
+			Content: part,
+		})
+	}
+
+	if group_id != 0 {
+		return SendGroupForwardMessageCustom(ws, group_id, messages)
+	}
+
+	return SendPrivateForwardMessageCustom(ws, user_id, messages)
+}
@@ -0,0 +1,344 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"koi/pkg/gocqhttp"
+	"koi/pkg/gocqhttp/cqcode"
+	"koi/pkg/gocqhttp/qq"
+
+	"github.com/gorilla/websocket"
+)
+
+// CQ码片段
+//
+// * 模板中类型为 Code 的值原样输出, 其他值均会被转义
+type Code string
+
+// 消息模板
+type Template struct {
+	template *template.Template
+}
+
+// 转义函数名
+const escaper = "_koi_escape"
+
+// 模板函数
+//
+// * card 函数需要在执行时绑定连接和群号, 此处仅为占位
+func funcs() template.FuncMap {
+	return template.FuncMap{
+		escaper: escape,
+		"at": func(uid any) (Code, error) {
+			id, err := integer(uid)
+			if err != nil {
+				return "", err
+			}
+
+			return Code(cqcode.At(qq.UserID(id), "")), nil
+		},
+		"face": func(face any) (Code, error) {
+			if name, ok := face.(string); ok {
+				info, ok := cqcode.FaceByName(name)
+				if !ok {
+					return "", fmt.Errorf("unknown face: %s", name)
+				}
+
+				return Code(cqcode.Face(info.ID)), nil
+			}
+
+			id, err := integer(face)
+			if err != nil {
+				return "", err
+			}
+
+			return Code(cqcode.Face(int(id))), nil
+		},
+		"image": func(file string) Code {
+			return Code(cqcode.Image(file, "", false))
+		},
+		"reply": func(message_id any) (Code, error) {
+			id, err := integer(message_id)
+			if err != nil {
+				return "", err
+			}
+
+			return Code(cqcode.Reply(qq.MessageID(id))), nil
+		},
+		"raw": func(text string) Code {
+			return Code(text)
+		},
+		"duration": duration,
+		"card": func(uid any) (string, error) {
+			return "", fmt.Errorf("card: no connection bound")
+		},
+	}
+}
+
+// 转换整数参数
+func integer(value any) (int64, error) {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), nil
+	case reflect.String:
+		return strconv.ParseInt(v.String(), 10, 64)
+	}
+
+	return 0, fmt.Errorf("not an integer: %v", value)
+}
+
+// 转义插值
+func escape(value any) Code {
+	switch value := value.(type) {
+	case Code:
+		return value
+	case nil:
+		return ""
+	}
+
+	return Code(cqcode.Escape(fmt.Sprint(value)))
+}
+
+// 格式化时长
+//
+// * 支持 time.Duration, qq.Duration 和整数秒, 如 1天2小时3分钟
+func duration(value any) (string, error) {
+	var d time.Duration
+
+	switch value := value.(type) {
+	case time.Duration:
+		d = value
+	case qq.Duration:
+		d = value.Duration
+	default:
+		seconds, err := integer(value)
+		if err != nil {
+			return "", err
+		}
+
+		d = time.Duration(seconds) * time.Second
+	}
+
+	if d < time.Minute {
+		return strconv.Itoa(int(d/time.Second)) + "秒", nil
+	}
+
+	var builder strings.Builder
+
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{24 * time.Hour, "天"},
+		{time.Hour, "小时"},
+		{time.Minute, "分钟"},
+	}
+
+	for _, u := range units {
+		if n := d / u.unit; n > 0 {
+			builder.WriteString(strconv.Itoa(int(n)))
+			builder.WriteString(u.name)
+
+			d -= n * u.unit
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// 创建模板
+func New(name string) *Template {
+	return &Template{template.New(name).Funcs(funcs())}
+}
+
+// 解析模板
+func (t *Template) Parse(text string) (*Template, error) {
+	_, err := t.template.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tmpl := range t.template.Templates() {
+		if tmpl.Tree != nil {
+			escapeNode(tmpl.Tree, tmpl.Tree.Root)
+		}
+	}
+
+	return t, nil
+}
+
+// 解析模板文件
+func ParseFile(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(filepath.Base(path)).Parse(string(data))
+}
+
+// 为所有输出添加转义
+func escapeNode(tree *parse.Tree, node parse.Node) {
+	switch node := node.(type) {
+	case *parse.ActionNode:
+		// 赋值语句不产生输出
+		if len(node.Pipe.Decl) > 0 {
+			return
+		}
+
+		cmds := node.Pipe.Cmds
+		if len(cmds) > 0 {
+			last := cmds[len(cmds)-1]
+			if len(last.Args) > 0 {
+				if id, ok := last.Args[0].(*parse.IdentifierNode); ok && id.Ident == escaper {
+					return
+				}
+			}
+		}
+
+		node.Pipe.Cmds = append(cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      node.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(escaper).SetTree(tree).SetPos(node.Pos)},
+		})
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+
+		for _, child := range node.Nodes {
+			escapeNode(tree, child)
+		}
+	case *parse.IfNode:
+		escapeNode(tree, node.List)
+		escapeNode(tree, node.ElseList)
+	case *parse.RangeNode:
+		escapeNode(tree, node.List)
+		escapeNode(tree, node.ElseList)
+	case *parse.WithNode:
+		escapeNode(tree, node.List)
+		escapeNode(tree, node.ElseList)
+	}
+}
+
+// 执行模板
+//
+// * ws 和 group_id 用于 card 函数查询群名片, 不需要时可传入 nil 和 0
+func (t *Template) Execute(ws *websocket.Conn, group_id qq.GroupID, data any) (string, error) {
+	tmpl, err := t.template.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	tmpl.Funcs(template.FuncMap{
+		"card": func(uid any) (string, error) {
+			if ws == nil || group_id == 0 {
+				return "", fmt.Errorf("card: no connection bound")
+			}
+
+			id, err := integer(uid)
+			if err != nil {
+				return "", err
+			}
+
+			member, err := gocqhttp.GetGroupMemberInfo(ws, group_id, qq.UserID(id))
+			if err != nil {
+				return "", err
+			}
+
+			if member.Card != "" {
+				return member.Card, nil
+			}
+
+			return member.Nickname, nil
+		},
+	})
+
+	var builder strings.Builder
+
+	err = tmpl.Execute(&builder, data)
+	if err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+// 模板集
+//
+// * 目录结构为 dir/<名称>.tmpl 和 dir/<群号>/<名称>.tmpl, 群目录中的模板优先
+type Set struct {
+	dir   string
+	mutex sync.Mutex
+	cache map[string]*Template
+}
+
+// 创建模板集
+func NewSet(dir string) *Set {
+	return &Set{dir: dir, cache: make(map[string]*Template)}
+}
+
+// 获取模板
+func (set *Set) Lookup(group_id qq.GroupID, name string) (*Template, error) {
+	paths := []string{filepath.Join(set.dir, name+".tmpl")}
+
+	if group_id != 0 {
+		group := filepath.Join(set.dir, group_id.String(), name+".tmpl")
+		paths = append([]string{group}, paths...)
+	}
+
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	for _, path := range paths {
+		if tmpl, ok := set.cache[path]; ok {
+			return tmpl, nil
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		tmpl, err := ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		set.cache[path] = tmpl
+
+		return tmpl, nil
+	}
+
+	return nil, fmt.Errorf("template not found: %s", name)
+}
+
+// 执行模板
+func (set *Set) Execute(ws *websocket.Conn, group_id qq.GroupID, name string, data any) (string, error) {
+	tmpl, err := set.Lookup(group_id, name)
+	if err != nil {
+		return "", err
+	}
+
+	return tmpl.Execute(ws, group_id, data)
+}
+
+// 重新加载模板
+func (set *Set) Reload() {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	set.cache = make(map[string]*Template)
+}
@@ -0,0 +1,95 @@
+package template
+
+import (
+	"testing"
+
+	"koi/pkg/gocqhttp/cqcode"
+)
+
+func TestEscape(t *testing.T) {
+	injected := "[CQ:at,qq=all]&"
+
+	tests := []struct {
+		name string
+		text string
+		data any
+		want string
+	}{
+		{"value", "hi {{.}}", injected, "hi &#91;CQ:at,qq=all&#93;&amp;"},
+		{"field", "{{.Name}}!", struct{ Name string }{injected}, "&#91;CQ:at,qq=all&#93;&amp;!"},
+		{"pipeline", `{{printf "%s." .}}`, injected, "&#91;CQ:at,qq=all&#93;&amp;."},
+		{"if", "{{if .}}{{.}}{{else}}none{{end}}", injected, "&#91;CQ:at,qq=all&#93;&amp;"},
+		{"range", "{{range .}}<{{.}}>{{end}}", []string{"[", "]"}, "<&#91;><&#93;>"},
+		{"with", "{{with .}}{{.}}{{end}}", injected, "&#91;CQ:at,qq=all&#93;&amp;"},
+		{"define", `{{define "x"}}{{.}}{{end}}{{template "x" .}}`, injected, "&#91;CQ:at,qq=all&#93;&amp;"},
+		{"variable", "{{$v := .}}{{$v}}", injected, "&#91;CQ:at,qq=all&#93;&amp;"},
+		{"nil", "[{{.}}]", nil, "[]"},
+
+		// 模板文本本身和 Code 不转义
+		{"literal", "[CQ:face,id=14]{{.}}", "x", "[CQ:face,id=14]x"},
+		{"at", "{{at 10001}} {{.}}", "[x]", cqcode.At(10001, "") + " &#91;x&#93;"},
+		{"face", `{{face "微笑"}}`, nil, cqcode.Face(14)},
+		{"raw", "{{raw .}}", "[CQ:face,id=14]", "[CQ:face,id=14]"},
+		{"code", "{{.}}", Code("[CQ:face,id=14]"), "[CQ:face,id=14]"},
+
+		// Code 经过其他函数后不再是 Code, 需要转义
+		{"code printf", `{{printf "%s" (raw .)}}`, "[x]", "&#91;x&#93;"},
+	}
+
+	for _, test := range tests {
+		tmpl, err := New(test.name).Parse(test.text)
+		if err != nil {
+			t.Errorf("%s: Parse: %v", test.name, err)
+			continue
+		}
+
+		got, err := tmpl.Execute(nil, 0, test.data)
+		if err != nil {
+			t.Errorf("%s: Execute: %v", test.name, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("%s: Execute = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseTwice(t *testing.T) {
+	tmpl, err := New("a").Parse("{{.}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 再次解析不会重复转义已解析的模板
+	_, err = tmpl.Parse(`{{define "b"}}{{.}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := tmpl.Execute(nil, 0, "&"); got != "&amp;" {
+		t.Errorf("Execute = %q, want %q", got, "&amp;")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tmpl, err := New("d").Parse("{{duration .}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := tmpl.Execute(nil, 0, 93784); got != "1天2小时3分钟" {
+		t.Errorf("duration = %q", got)
+	}
+}
+
+func TestCardUnbound(t *testing.T) {
+	tmpl, err := New("c").Parse("{{card 1}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tmpl.Execute(nil, 0, nil); err == nil {
+		t.Error("card without connection succeeded")
+	}
+}
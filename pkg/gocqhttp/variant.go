@@ -0,0 +1,38 @@
+package gocqhttp
+
+import (
+	"context"
+	"strings"
+)
+
+// 调用方式
+const (
+	VariantSync        = ""              // 同步调用, 等待执行结果
+	VariantAsync       = "_async"        // 异步调用, 不等待执行结果
+	VariantRateLimited = "_rate_limited" // 限速调用, 按 go-cqhttp 的限速配置排队执行
+)
+
+type variantKey struct{}
+
+// 指定单次调用的调用方式
+//
+// * 优先于连接设置中的 Options.Variants
+//
+// * 用于 Call, CallAs 和带 Context 后缀的函数
+func WithVariant(ctx context.Context, variant string) context.Context {
+	return context.WithValue(ctx, variantKey{}, variant)
+}
+
+// 添加调用方式后缀
+func withVariant(ctx context.Context, action string, variants map[string]string) string {
+	if strings.HasSuffix(action, VariantAsync) || strings.HasSuffix(action, VariantRateLimited) {
+		return action
+	}
+
+	variant, ok := ctx.Value(variantKey{}).(string)
+	if !ok {
+		variant = variants[action]
+	}
+
+	return action + variant
+}
@@ -0,0 +1,48 @@
+package gocqhttp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestVariants(t *testing.T) {
+	server, ws := newFakeServer(t, nil)
+
+	Configure(ws, Options{Variants: map[string]string{"send_msg": VariantRateLimited}})
+
+	if _, err := SendGroupText(ws, 1, "hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetGroupBanContext(WithVariant(context.Background(), VariantAsync), ws, 1, 2, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// 单次调用的设置优先于连接设置
+	if _, err := SendGroupTextContext(WithVariant(context.Background(), VariantSync), ws, 1, "hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetGroupBan(ws, 1, 2, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"send_msg_rate_limited", "set_group_ban_async", "send_msg", "set_group_ban"}
+
+	if got := server.Actions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("actions = %v, want %v", got, want)
+	}
+}
+
+func TestVariantAsyncResponse(t *testing.T) {
+	_, ws := newFakeServer(t, func(string, json.RawMessage) map[string]any {
+		return map[string]any{"status": "async", "retcode": 1, "data": nil}
+	})
+
+	message_id, err := SendGroupTextContext(WithVariant(context.Background(), VariantAsync), ws, 1, "hello")
+	if err != nil || message_id != 0 {
+		t.Errorf("SendGroupTextContext = %v, %v, want 0, nil", message_id, err)
+	}
+}